@@ -14,7 +14,7 @@
 from kitty.constants import cache_dir
 from kitty.fast_data_types import truncate_point_for_length, wcswidth
 from kitty.typing import BossType, KeyEventType, TypedDict
-from kitty.utils import ScreenSize
+from kitty.utils import ScreenSize, locked_file
 
 from ..tui.handler import Handler, result_handler
 from ..tui.loop import Loop, MouseEvent, debug
@@ -70,7 +70,8 @@ def __enter__(self) -> 'HistoryCompleter':
 
     def __exit__(self, *a: object) -> None:
         if self.history_path:
-            readline.write_history_file(self.history_path)
+            with locked_file(self.history_path + '.lock'):
+                readline.write_history_file(self.history_path)
 
 
 def option_text() -> str:
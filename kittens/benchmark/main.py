@@ -0,0 +1,193 @@
+#!/usr/bin/env python
+# License: GPLv3 Copyright: 2022, Kovid Goyal <kovid at kovidgoyal.net>
+
+import random
+import re
+import sys
+from base64 import standard_b64encode
+from itertools import repeat
+from time import monotonic
+from typing import Callable, Dict, List, Tuple
+
+from kitty.cli import parse_args
+from kitty.cli_stub import BenchmarkCLIOptions
+from kitty.constants import appname
+from kitty.utils import TTYIO
+
+OPTIONS = r'''
+--size -s
+type=int
+default=8
+The amount of data (in MiB) to send to the terminal for each of the
+throughput tests.
+
+
+--latency-count
+type=int
+default=100
+The number of query round trips to use for the :code:`latency` test.
+
+
+--timeout
+type=float
+default=120
+The maximum amount of time (in seconds) to wait for the terminal to finish
+processing the data for a single test.
+'''.format
+help_text = '''\
+Measure the performance of the terminal this kitten is run in. The throughput
+tests send a large amount of data to the terminal and measure how long it takes
+for the terminal to process it, by waiting for the response to a primary device
+attributes query sent after the data. The latency test measures the round trip
+time for that query. Specify the names of the tests to run, by default all tests
+are run. Available tests: {}.
+'''
+usage = '[test ...]'
+DA1 = b'\x1b[c'
+da1_pat = re.compile(br'\x1b\[\?[0-9;]*c')
+MiB = 1024 * 1024
+
+
+class Timeout(Exception):
+    pass
+
+
+def wait_for_da1(ttyio: TTYIO, timeout: float) -> None:
+    received = b''
+    found = False
+
+    def more_needed(data: bytes) -> bool:
+        nonlocal received, found
+        if b'\x03' in data:
+            raise KeyboardInterrupt()
+        received += data
+        if da1_pat.search(received) is not None:
+            found = True
+            return False
+        return True
+
+    ttyio.recv(more_needed, timeout=timeout, sz=4096)
+    if not found:
+        raise Timeout()
+
+
+def lines_of(words: Callable[[], str], size: int) -> bytes:
+    ans: List[bytes] = []
+    total = 0
+    while total < size:
+        line = (words() + '\r\n').encode('utf-8')
+        ans.append(line)
+        total += len(line)
+    return b''.join(ans)
+
+
+def plain_text(rng: random.Random) -> bytes:
+    chars = ''.join(chr(i) for i in range(ord(' '), ord('~') + 1))
+    return lines_of(lambda: ''.join(rng.choice(chars) for i in range(rng.randint(0, 110))), MiB)
+
+
+def colored_text(rng: random.Random) -> bytes:
+    chars = 'abcdefghijklmnopqrstuvwxyz'
+
+    def word() -> str:
+        w = ''.join(rng.choice(chars) for i in range(rng.randint(1, 10)))
+        return f'\x1b[38;5;{rng.randint(0, 255)};48;5;{rng.randint(0, 255)}m{w}\x1b[m'
+
+    return lines_of(lambda: ' '.join(word() for i in range(rng.randint(0, 12))), MiB)
+
+
+def unicode_text(rng: random.Random) -> bytes:
+    chars = (
+        'абвгдежзийклмнопрстуфхцчшщъыьэюя' 'αβγδεζηθικλμνξοπρστυφχψω'
+        '日本語の文章と中文的句子한국어문장' '😀😃😄😁😆😅🤣😂🙂🙃😉😊🐱🐶🦊🐼'
+        'éäôñ'
+    )
+    return lines_of(lambda: ''.join(rng.choice(chars) for i in range(rng.randint(0, 50))), MiB)
+
+
+def graphics(rng: random.Random) -> bytes:
+    width = height = 256
+    rgba = bytes(rng.getrandbits(8) for i in range(width * height * 4))
+    data = standard_b64encode(rgba)
+    ans: List[bytes] = []
+    pos = 0
+    while pos < len(data):
+        chunk, pos = data[pos:pos + 4096], pos + 4096
+        m = b'1' if pos < len(data) else b'0'
+        if len(ans):
+            ans.append(b'\x1b_Gm=' + m + b';' + chunk + b'\x1b\\')
+        else:
+            ans.append(f'\x1b_Ga=T,f=32,q=2,C=1,s={width},v={height},m='.encode('ascii') + m + b';' + chunk + b'\x1b\\')
+    ans.append(b'\x1b_Ga=d,d=A,q=2\x1b\\')
+    return b''.join(ans)
+
+
+throughput_tests: Dict[str, Callable[[random.Random], bytes]] = {
+    'plain': plain_text, 'color': colored_text, 'unicode': unicode_text, 'graphics': graphics,
+}
+all_tests = tuple(throughput_tests) + ('latency',)
+
+
+def run_throughput_test(ttyio: TTYIO, data: bytes, size: int, timeout: float) -> Tuple[int, float]:
+    count = max(1, size // len(data))
+    start = monotonic()
+    ttyio.send(repeat(data, count))
+    ttyio.send(DA1)
+    wait_for_da1(ttyio, timeout)
+    return count * len(data), monotonic() - start
+
+
+def run_latency_test(ttyio: TTYIO, count: int, timeout: float) -> List[float]:
+    ans = []
+    for i in range(max(1, count)):
+        start = monotonic()
+        ttyio.send(DA1)
+        wait_for_da1(ttyio, timeout)
+        ans.append(monotonic() - start)
+    return ans
+
+
+def format_rate(num_bytes: int, duration: float) -> str:
+    return f'{num_bytes / MiB / max(duration, 1e-9):.1f} MiB/s'
+
+
+def main(args: List[str] = sys.argv) -> None:
+    cli_opts, items = parse_args(
+        args[1:], OPTIONS, usage, help_text.format(', '.join(all_tests)), f'{appname} +kitten benchmark',
+        result_class=BenchmarkCLIOptions)
+    unknown = [x for x in items if x not in all_tests]
+    if unknown:
+        raise SystemExit(f'Unknown test: {unknown[0]}. Choose from: {", ".join(all_tests)}')
+    tests = [x for x in all_tests if not items or x in items]
+    rng = random.Random(0)
+    results: List[Tuple[str, str]] = []
+    with TTYIO() as ttyio:
+        try:
+            for name in tests:
+                ttyio.send('\x1b[H\x1b[2J')
+                if name == 'latency':
+                    times = run_latency_test(ttyio, cli_opts.latency_count, cli_opts.timeout)
+                    results.append((name, 'min: {:.2f} ms avg: {:.2f} ms max: {:.2f} ms'.format(
+                        min(times) * 1000, sum(times) * 1000 / len(times), max(times) * 1000)))
+                else:
+                    num_bytes, duration = run_throughput_test(
+                        ttyio, throughput_tests[name](rng), cli_opts.size * MiB, cli_opts.timeout)
+                    results.append((name, f'{format_rate(num_bytes, duration)} ({num_bytes / MiB:.1f} MiB in {duration:.2f} s)'))
+        except KeyboardInterrupt:
+            raise SystemExit('Interrupted by user')
+        except Timeout:
+            raise SystemExit('Timed out waiting for the terminal to respond')
+        finally:
+            ttyio.send('\x1b[m\x1b[H\x1b[2J')
+    width = max(len(name) for name, r in results) if results else 0
+    for name, result in results:
+        print(f'{name:<{width}}  {result}')
+
+
+if __name__ == '__main__':
+    main()
+elif __name__ == '__doc__':
+    cd = sys.cli_docs  # type: ignore
+    cd['usage'] = usage
+    cd['options'] = OPTIONS
+    cd['help_text'] = help_text.format(', '.join(all_tests))
@@ -5,8 +5,11 @@
 import io
 import os
 import select
+import shlex
+import subprocess
 import sys
-from typing import List, NoReturn, Optional
+import time
+from typing import BinaryIO, List, NoReturn, Optional
 
 from kitty.cli import parse_args
 from kitty.cli_stub import ClipboardCLIOptions
@@ -37,6 +40,30 @@
 type=bool-set
 Wait till the copy to clipboard is complete before exiting. Useful if running
 the kitten in a dedicated, ephemeral window.
+
+
+--filter
+A command to pipe the clipboard contents through. When reading the clipboard,
+the output of the command is printed instead of the clipboard contents. When
+writing to the clipboard, the data from STDIN is passed through the command
+before being placed on the clipboard. The command line is split using shell
+quoting rules.
+
+
+--watch
+default=False
+type=bool-set
+Keep running and print the contents of the clipboard every time they change.
+Since this reads the clipboard repeatedly, you will want to set
+:opt:`clipboard_control` to allow reading the clipboard without a confirmation
+prompt. Cannot be used together with data piped in on STDIN.
+
+
+--watch-interval
+default=1
+type=float
+The interval, in seconds, at which to check for clipboard changes when using
+:option:`--watch`.
 '''.format
 help_text = '''\
 Read or write to the system clipboard.
@@ -90,29 +117,82 @@ def on_osc(osc: str) -> None:
             clipboard_contents = standard_b64decode(data[widx+1:]).decode('utf-8')
 
 
-def wait_loop(tty_fd: int) -> None:
+def read_responses(tty_fd: int, timeout: Optional[float] = None) -> None:
+    # Must be called with the tty in raw mode. Returns when a response is
+    # received or, if timeout is not None, once it has elapsed.
     os.set_blocking(tty_fd, False)
     decoder = codecs.getincrementaldecoder('utf-8')('ignore')
+    deadline = None if timeout is None else time.monotonic() + timeout
+    buf = ''
+    while not got_capability_response and not got_clipboard_response:
+        remaining = None if deadline is None else deadline - time.monotonic()
+        if remaining is not None and remaining <= 0:
+            break
+        rd = select.select([tty_fd], [], [], remaining)[0]
+        if rd:
+            raw = os.read(tty_fd, io.DEFAULT_BUFFER_SIZE)
+            if not raw:
+                raise EOFError()
+            data = decoder.decode(raw)
+            buf = (buf + data) if buf else data
+            buf = parse_input_from_terminal(on_text, on_dcs, ignore, on_osc, ignore, ignore, buf, False)
+
+
+def wait_loop(tty_fd: int) -> None:
+    with raw_mode(tty_fd):
+        read_responses(tty_fd)
+
+
+def run_filter(cmd: str, data: bytes) -> bytes:
+    try:
+        p = subprocess.run(shlex.split(cmd), input=data, stdout=subprocess.PIPE)
+    except OSError as err:
+        raise SystemExit(f'Failed to run the filter command: {cmd} with error: {err}')
+    if p.returncode != 0:
+        raise SystemExit(f'The filter command: {cmd} failed with exit code: {p.returncode}')
+    return p.stdout
+
+
+def output_clipboard_contents(filter_cmd: str) -> None:
+    if filter_cmd:
+        sys.stdout.buffer.write(run_filter(filter_cmd, clipboard_contents.encode('utf-8')))
+        sys.stdout.flush()
+    else:
+        print(end=clipboard_contents, flush=True)
+
+
+def watch_clipboard(tty_fd: int, ttyf: BinaryIO, use_primary: bool, interval: float, filter_cmd: str) -> None:
+    global got_clipboard_response
+    request = request_from_clipboard(use_primary).encode('ascii')
+    previous = ''
+    # Stay in raw mode for the whole watch so that keystrokes typed between
+    # polls are not echoed and Ctrl+C/Ctrl+D are handled while sleeping
     with raw_mode(tty_fd):
-        buf = ''
-        while not got_capability_response and not got_clipboard_response:
-            rd = select.select([tty_fd], [], [])[0]
-            if rd:
-                raw = os.read(tty_fd, io.DEFAULT_BUFFER_SIZE)
-                if not raw:
-                    raise EOFError()
-                data = decoder.decode(raw)
-                buf = (buf + data) if buf else data
-                buf = parse_input_from_terminal(on_text, on_dcs, ignore, on_osc, ignore, ignore, buf, False)
+        while True:
+            got_clipboard_response = False
+            ttyf.write(request)
+            ttyf.flush()
+            read_responses(tty_fd)
+            if clipboard_contents and clipboard_contents != previous:
+                previous = clipboard_contents
+                output_clipboard_contents(filter_cmd)
+                if not filter_cmd and not clipboard_contents.endswith('\n'):
+                    print(flush=True)
+            got_clipboard_response = False
+            read_responses(tty_fd, max(0.05, interval))
 
 
 def main(args: List[str]) -> NoReturn:
     cli_opts, items = parse_args(args[1:], OPTIONS, usage, help_text, 'kitty +kitten clipboard', result_class=ClipboardCLIOptions)
     if items:
         raise SystemExit('Unrecognized extra command line arguments')
+    if cli_opts.watch and not sys.stdin.isatty():
+        raise SystemExit('Cannot use --watch when data is piped in on STDIN')
     data: Optional[bytes] = None
     if not sys.stdin.isatty():
         data = sys.stdin.buffer.read()
+        if data and cli_opts.filter:
+            data = run_filter(cli_opts.filter, data)
     wait_for_capability_response = False
     data_to_write = []
     if data:
@@ -120,7 +200,7 @@ def main(args: List[str]) -> NoReturn:
         if not cli_opts.get_clipboard and cli_opts.wait_for_completion:
             data_to_write.append(b'\x1bP+q544e\x1b\\')
             wait_for_capability_response = True
-    if cli_opts.get_clipboard:
+    if cli_opts.get_clipboard and not cli_opts.watch:
         data_to_write.append(request_from_clipboard(cli_opts.use_primary).encode('ascii'))
         wait_for_capability_response = True
     tty_fd = os.open(os.ctermid(), os.O_RDWR | os.O_CLOEXEC)
@@ -129,16 +209,19 @@ def main(args: List[str]) -> NoReturn:
         for x in data_to_write:
             ttyf.write(x)
         ttyf.flush()
-        if wait_for_capability_response:
+        if wait_for_capability_response or cli_opts.watch:
             try:
-                wait_loop(tty_fd)
+                if cli_opts.watch:
+                    watch_clipboard(tty_fd, ttyf, cli_opts.use_primary, cli_opts.watch_interval, cli_opts.filter)
+                else:
+                    wait_loop(tty_fd)
             except KeyboardInterrupt:
                 sys.excepthook = lambda *a: None
                 raise
             except EOFError:
                 retcode = 1
-    if clipboard_contents:
-        print(end=clipboard_contents)
+    if clipboard_contents and not cli_opts.watch:
+        output_clipboard_contents(cli_opts.filter)
 
     raise SystemExit(retcode)
 
@@ -21,8 +21,8 @@
 remote_dirs: Dict[str, str] = {}
 
 
-def add_remote_dir(val: str) -> None:
-    remote_dirs[val] = os.path.basename(val).rpartition('-')[-1]
+def add_remote_dir(val: str, label: Optional[str] = None) -> None:
+    remote_dirs[val] = os.path.basename(val).rpartition('-')[-1] if label is None else label
 
 
 class Segment:
@@ -146,13 +146,16 @@ def collect_files(collection: Collection, left: str, right: str) -> None:
 
     removed = left_names - common_names
     added = right_names - common_names
-    ahash = {a: hash_for_path(right_path_map[a]) for a in added}
-    rhash = {r: hash_for_path(left_path_map[r]) for r in removed}
-    for name, rh in rhash.items():
-        for n, ah in ahash.items():
-            if ah == rh and data_for_path(left_path_map[name]) == data_for_path(right_path_map[n]):
+    added_by_hash: Dict[bytes, List[str]] = {}
+    for a in sorted(added):
+        added_by_hash.setdefault(hash_for_path(right_path_map[a]), []).append(a)
+    for name in sorted(removed):
+        candidates = added_by_hash.get(hash_for_path(left_path_map[name]), [])
+        for n in candidates:
+            if data_for_path(left_path_map[name]) == data_for_path(right_path_map[n]):
                 collection.add_rename(left_path_map[name], right_path_map[n])
                 added.discard(n)
+                candidates.remove(n)
                 break
         else:
             collection.add_removal(left_path_map[name])
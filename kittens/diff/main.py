@@ -14,7 +14,8 @@
 from functools import partial
 from gettext import gettext as _
 from typing import (
-    Any, DefaultDict, Dict, Iterable, Iterator, List, Optional, Tuple, Union
+    Any, Callable, DefaultDict, Dict, Iterable, Iterator, List, Optional,
+    Sequence, Tuple, Union
 )
 
 from kitty.cli import CONFIG_HELP, parse_args
@@ -33,7 +34,7 @@
 from . import global_data
 from .collect import (
     Collection, add_remote_dir, create_collection, data_for_path,
-    lines_for_path, sanitize, set_highlight_data
+    lines_for_path, path_name_map, sanitize, set_highlight_data
 )
 from .config import init_config
 from .options.types import Options as DiffOptions
@@ -92,6 +93,7 @@ def generate_diff(collection: Collection, context: int) -> Union[str, Dict[str,
 class DiffHandler(Handler):
 
     image_manager_class = ImageManager
+    focus_tracking = True
 
     def __init__(self, args: DiffCLIOptions, opts: DiffOptions, left: str, right: str) -> None:
         self.state = State.initializing
@@ -148,6 +150,54 @@ def perform_action(self, action: KeyAction) -> None:
             if func == 'start_search':
                 self.start_search(bool(args[0]), bool(args[1]))
                 return
+            if func == 'copy_to_clipboard':
+                self.copy_to_clipboard(str(args[0]))
+                return
+
+    def copy_to_clipboard(self, what: str) -> None:
+        text = ''
+        if self.state is State.diffed and self.diff_lines:
+            ref = self.current_position
+            if what == 'path':
+                text = path_name_map.get(ref.path, ref.path)
+            elif isinstance(ref.extra, LineRef):
+                if what == 'line':
+                    data = data_for_path(ref.path)
+                    lines = data.splitlines() if isinstance(data, str) else ()
+                    if ref.extra.src_line_number < len(lines):
+                        text = lines[ref.extra.src_line_number]
+                else:
+                    text = self.hunk_as_unified_diff(ref.path, ref.extra.src_line_number)
+        if text:
+            self.cmd.write_to_clipboard(text)
+        else:
+            self.cmd.bell()
+
+    def hunk_as_unified_diff(self, path: str, line_number: int) -> str:
+        for left_path, item_type, right_path in self.collection:
+            if item_type != 'diff' or right_path is None or path not in (left_path, right_path):
+                continue
+            patch = self.diff_map.get(left_path)
+            if patch is None:
+                continue
+            is_left = path == left_path
+            for hunk in patch:
+                start, count = (hunk.left_start, hunk.left_count) if is_left else (hunk.right_start, hunk.right_count)
+                if start <= line_number < start + max(1, count):
+                    left_lines, right_lines = (str(data_for_path(x)).splitlines() for x in (left_path, right_path))
+                    ans = [
+                        f'--- a/{path_name_map.get(left_path, left_path)}',
+                        f'+++ b/{path_name_map.get(right_path, right_path)}',
+                        f'@@ -{hunk.left_start + 1},{hunk.left_count} +{hunk.right_start + 1},{hunk.right_count} @@ {hunk.title}'.rstrip(),
+                    ]
+                    for chunk in hunk.chunks:
+                        if chunk.is_context:
+                            ans.extend(' ' + x for x in left_lines[chunk.left_start:chunk.left_start + chunk.left_count])
+                        else:
+                            ans.extend('-' + x for x in left_lines[chunk.left_start:chunk.left_start + chunk.left_count])
+                            ans.extend('+' + x for x in right_lines[chunk.right_start:chunk.right_start + chunk.right_count])
+                    return '\n'.join(ans) + '\n'
+        return ''
 
     def create_collection(self) -> None:
 
@@ -319,6 +369,7 @@ def init_terminal_state(self) -> None:
             fg=self.opts.foreground, bg=self.opts.background,
             cursor=self.opts.foreground, select_fg=self.opts.select_fg,
             select_bg=self.opts.select_bg)
+        self.focus_dimmed_colors = self.opts.background, self.opts.foreground, self.opts.select_bg
         self.cmd.set_cursor_shape('bar')
 
     def finalize(self) -> None:
@@ -585,6 +636,22 @@ def on_eot(self) -> None:
 Override individual configuration options, can be specified multiple times.
 Syntax: :italic:`name=value`. For example: :italic:`-o background=gray`
 
+
+--git
+type=bool-set
+Show the changes in the git repository containing the current directory,
+instead of comparing two files or directories. With no arguments, the
+changes in the working tree that have not been staged are shown. With a single
+revision argument, the working tree is compared to that revision. With two
+revisions, or a range such as :code:`A..B` or :code:`A...B`, the two revisions are
+compared. Untracked files are ignored, as with :program:`git diff`.
+
+
+--staged --cached
+type=bool-set
+When used with :option:`--git`, show the staged changes, comparing the index
+to :code:`HEAD` or the specified revision.
+
 '''.format, config_help=CONFIG_HELP.format(conf_name='diff', appname=appname))
 
 
@@ -599,7 +666,8 @@ def __call__(self, message: Any, category: Any, filename: str, lineno: int, file
 
 
 showwarning = ShowWarning()
-help_text = 'Show a side-by-side diff of the specified files/directories. You can also use :italic:`ssh:hostname:remote-file-path` to diff remote files.'
+help_text = ('Show a side-by-side diff of the specified files/directories. You can also use :italic:`ssh:hostname:remote-file-path` to diff remote files.'
+             ' Use :option:`--git` to show the changes in a git repository.')
 usage = 'file_or_directory_left file_or_directory_right'
 
 
@@ -637,6 +705,102 @@ def get_ssh_file(hostname: str, rpath: str) -> str:
         return os.path.abspath(os.path.join(tdir, rpath))
 
 
+def git_output(cwd: str, *args: str) -> bytes:
+    p = subprocess.run(['git', '-C', cwd] + list(args), stdout=subprocess.PIPE)
+    if p.returncode != 0:
+        raise SystemExit(p.returncode)
+    return p.stdout
+
+
+def parse_git_revspec(revspec: Sequence[str], staged: bool, merge_base: Callable[[str, str], str]) -> Tuple[Optional[str], Optional[str]]:
+    # None means the working tree and the empty string the index
+    left: Optional[str] = ''
+    right: Optional[str] = None
+    if len(revspec) > 2:
+        raise SystemExit('You must specify at most two revisions to compare')
+    if len(revspec) == 2:
+        left, right = revspec
+    elif len(revspec) == 1:
+        left = revspec[0]
+        if '..' in left:
+            sep = '...' if '...' in left else '..'
+            left, right = (x or 'HEAD' for x in left.split(sep, 1))
+            if sep == '...':
+                left = merge_base(left, right)
+    if staged:
+        if right is not None:
+            raise SystemExit('Cannot compare two revisions when showing staged changes')
+        left, right = left or 'HEAD', ''
+    return left, right
+
+
+def get_git_dirs(revspec: Sequence[str], staged: bool) -> Tuple[str, str, str]:
+    import shutil
+    toplevel = git_output(os.getcwd(), 'rev-parse', '--show-toplevel').decode('utf-8').rstrip('\n')
+    left, right = parse_git_revspec(
+        revspec, staged, lambda a, b: git_output(toplevel, 'merge-base', a, b).decode('utf-8').strip())
+    args = ['diff', '--raw', '--no-abbrev', '-z', '--no-renames']
+    if right == '':
+        args.append('--cached')
+    args.extend(x for x in (left, right) if x)
+    raw = git_output(toplevel, *args).decode('utf-8').split('\0')
+    changed: List[str] = []
+    # changed submodules are shown as the commit they point to, like git diff does
+    submodule_commits: Tuple[Dict[str, str], Dict[str, str]] = {}, {}
+    for meta, path in zip(raw[::2], raw[1::2]):
+        modes_and_ids = meta.lstrip(':').split()
+        if len(modes_and_ids) < 4:
+            continue
+        changed.append(path)
+        for side in (0, 1):
+            if modes_and_ids[side] == '160000' and modes_and_ids[side + 2].strip('0'):
+                submodule_commits[side][path] = modes_and_ids[side + 2]
+    if not changed:
+        raise SystemExit('No changes found')
+
+    def label(rev: Optional[str]) -> str:
+        return 'working tree' if rev is None else (rev or 'index')
+
+    def working_tree_submodule_commit(src: str) -> Optional[str]:
+        if not os.path.lexists(os.path.join(src, '.git')):
+            return None
+        p = subprocess.run(['git', '-C', src, 'rev-parse', 'HEAD'], stdout=subprocess.PIPE, stderr=subprocess.DEVNULL)
+        if p.returncode != 0:
+            return None
+        return p.stdout.decode('utf-8').strip() or None
+
+    def materialize(rev: Optional[str], commits: Dict[str, str]) -> str:
+        tdir = tempfile.mkdtemp()
+        add_remote_dir(tdir, label(rev))
+        atexit.register(shutil.rmtree, tdir)
+        for path in changed:
+            dest = os.path.join(tdir, path)
+            data: Optional[bytes] = None
+            commit = commits.get(path)
+            if rev is None:
+                src = os.path.join(toplevel, path)
+                if os.path.isdir(src) and not os.path.islink(src):
+                    commit = working_tree_submodule_commit(src) or commit
+                elif os.path.lexists(src):
+                    os.makedirs(os.path.dirname(dest), exist_ok=True)
+                    shutil.copy2(src, dest, follow_symlinks=False)
+            elif commit is None:
+                p = subprocess.run(['git', '-C', toplevel, 'show', f'{rev}:{path}'], stdout=subprocess.PIPE, stderr=subprocess.DEVNULL)
+                if p.returncode == 0:
+                    data = p.stdout
+            if commit is not None:
+                data = f'Subproject commit {commit}\n'.encode('ascii')
+            if data is not None:
+                os.makedirs(os.path.dirname(dest), exist_ok=True)
+                with open(dest, 'wb') as f:
+                    f.write(data)
+        return tdir
+
+    return (
+        materialize(left, submodule_commits[0]), materialize(right, submodule_commits[1]),
+        _('{} vs. {}').format(label(left), label(right)))
+
+
 def get_remote_file(path: str) -> str:
     if path.startswith('ssh:'):
         parts = path.split(':', 2)
@@ -648,10 +812,13 @@ def get_remote_file(path: str) -> str:
 def main(args: List[str]) -> None:
     warnings.showwarning = showwarning
     cli_opts, items = parse_args(args[1:], OPTIONS, usage, help_text, 'kitty +kitten diff', result_class=DiffCLIOptions)
-    if len(items) != 2:
-        raise SystemExit('You must specify exactly two files/directories to compare')
-    left, right = items
-    global_data.title = _('{} vs. {}').format(left, right)
+    if cli_opts.git:
+        left, right, global_data.title = get_git_dirs(items, cli_opts.staged)
+    else:
+        if len(items) != 2:
+            raise SystemExit('You must specify exactly two files/directories to compare')
+        left, right = items
+        global_data.title = _('{} vs. {}').format(left, right)
     opts = init_config(cli_opts)
     set_diff_command(opts.diff_cmd)
     lines_for_path.replace_tab_by = opts.replace_tab_by
@@ -260,4 +260,21 @@
 map('Search backward (no regex)',
     'search_backward_simple b start_search substring backward',
     )
+
+map('Copy the current hunk to the clipboard',
+    'copy_hunk y copy_to_clipboard hunk',
+    long_text='''
+Copy the hunk at the top of the screen to the clipboard, in unified diff format.
+The clipboard is written to using the OSC 52 escape code, so this works over SSH
+as well.
+'''
+    )
+
+map('Copy the current line to the clipboard',
+    'copy_line l copy_to_clipboard line',
+    )
+
+map('Copy the current file path to the clipboard',
+    'copy_path c copy_to_clipboard path',
+    )
 egr()  # }}}
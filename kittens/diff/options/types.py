@@ -170,4 +170,10 @@ def __getitem__(self, key: typing.Union[int, str]) -> typing.Any:
     (ParsedShortcut(mods=0, key_name='f'), KeyAction('start_search', (False, False))),  # noqa
     # search_backward_simple
     (ParsedShortcut(mods=0, key_name='b'), KeyAction('start_search', (False, True))),  # noqa
+    # copy_hunk
+    (ParsedShortcut(mods=0, key_name='y'), KeyAction('copy_to_clipboard', ('hunk',))),  # noqa
+    # copy_line
+    (ParsedShortcut(mods=0, key_name='l'), KeyAction('copy_to_clipboard', ('line',))),  # noqa
+    # copy_path
+    (ParsedShortcut(mods=0, key_name='c'), KeyAction('copy_to_clipboard', ('path',))),  # noqa
 ]
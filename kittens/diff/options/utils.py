@@ -49,6 +49,14 @@ def parse_start_search(func: str, rest: str) -> Tuple[str, Tuple[bool, bool]]:
     return func, (is_regex, is_backward)
 
 
+@func_with_args('copy_to_clipboard')
+def parse_copy_to_clipboard(func: str, rest: str) -> Tuple[str, str]:
+    rest = rest.lower()
+    if rest not in {'hunk', 'line', 'path'}:
+        rest = 'hunk'
+    return func, rest
+
+
 def syntax_aliases(raw: str) -> Dict[str, str]:
     ans = {}
     for x in raw.split():
@@ -59,7 +59,8 @@
 --background
 default=none
 Specify a background color, this will cause transparent images to be composited
-on top of the specified color.
+on top of the specified color. Use the special value :code:`terminal` to use
+the current background color of the terminal.
 
 
 --mirror
@@ -138,11 +139,25 @@
 --hold
 type=bool-set
 Wait for a key press before exiting after displaying the images.
+
+
+--passthrough
+type=choices
+choices=none,tmux
+default=none
+Wrap graphics escape codes so that they pass through terminal multiplexers such
+as tmux to the terminal. Use :code:`tmux` when running inside tmux. Note that
+tmux must be configured with :code:`set -g allow-passthrough on` for this to
+work, and since tmux does not know about the images, they are not moved when the
+pane is scrolled or switched. When using passthrough, support for the graphics
+protocol cannot be detected, so the :italic:`stream` transfer mode is used,
+unless :option:`--transfer-mode` is specified.
 '''
 
 
 screen_size: Optional[ScreenSizeGetter] = None
 can_transfer_with_files = False
+passthrough_mode = 'none'
 
 
 def get_screen_size_function() -> ScreenSizeGetter:
@@ -162,8 +177,15 @@ def options_spec() -> str:
     return OPTIONS.format(appname=f'{appname}-icat')
 
 
+def tmux_passthrough(data: bytes) -> bytes:
+    return b'\033Ptmux;' + data.replace(b'\033', b'\033\033') + b'\033\\'
+
+
 def write_gr_cmd(cmd: GraphicsCommand, payload: Optional[bytes] = None) -> None:
-    sys.stdout.buffer.write(cmd.serialize(payload or b''))
+    data = cmd.serialize(payload or b'')
+    if passthrough_mode == 'tmux':
+        data = tmux_passthrough(data)
+    sys.stdout.buffer.write(data)
     sys.stdout.flush()
 
 
@@ -424,6 +446,26 @@ def more_needed(data: bytes) -> bool:
     return responses.get(1, False)
 
 
+def query_terminal_background(wait_for: float = 10) -> Optional[str]:
+    received = b''
+    ans: Optional[str] = None
+    pat = re.compile(br'\033]11;rgb:([0-9a-fA-F]+)/([0-9a-fA-F]+)/([0-9a-fA-F]+)(?:\033\\|\a)')
+
+    def more_needed(data: bytes) -> bool:
+        nonlocal received, ans
+        received += data
+        m = pat.search(received)
+        if m is None:
+            return True
+        ans = '#' + ''.join(f'{round(int(x, 16) * 255 / (16 ** len(x) - 1)):02x}' for x in m.groups())
+        return False
+
+    with TTYIO() as io:
+        io.send('\033]11;?\033\\')
+        io.recv(more_needed, timeout=wait_for)
+    return ans
+
+
 class Place(NamedTuple):
     width: int
     height: int
@@ -445,6 +487,7 @@ def parse_place(raw: str) -> Optional[Place]:
         ' You can specify multiple image files and/or directories.'
         ' Directories are scanned recursively for image files. If STDIN'
         ' is not a terminal, image data will be read from it as well.'
+        ' Use the special name - to read image data from STDIN explicitly.'
         ' You can also specify HTTP(S) or FTP URLs which will be'
         ' automatically downloaded and displayed.'
 )
@@ -509,7 +552,7 @@ def process_single_item(
 
 
 def main(args: List[str] = sys.argv) -> None:
-    global can_transfer_with_files
+    global can_transfer_with_files, passthrough_mode
     cli_opts, items_ = parse_args(args[1:], options_spec, usage, help_text, f'{appname} +kitten icat', result_class=IcatCLIOptions)
     items: List[Union[str, bytes]] = list(items_)
 
@@ -528,9 +571,15 @@ def main(args: List[str] = sys.argv) -> None:
     if not sys.stdout.isatty():
         sys.stdout = open(os.ctermid(), 'w')
     stdin_data = None
-    if cli_opts.stdin == 'yes' or (cli_opts.stdin == 'detect' and sys.stdin is not None and not sys.stdin.isatty()):
+    read_stdin_explicitly = '-' in items
+    if read_stdin_explicitly or cli_opts.stdin == 'yes' or (
+            cli_opts.stdin == 'detect' and sys.stdin is not None and not sys.stdin.isatty()):
         stdin_data = sys.stdin.buffer.read()
-        if stdin_data:
+        if read_stdin_explicitly:
+            if not stdin_data:
+                raise SystemExit('No image data was read from STDIN')
+            items = [stdin_data if x == '-' else x for x in items]
+        elif stdin_data:
             items.insert(0, stdin_data)
         sys.stdin.close()
         sys.stdin = open(os.ctermid())
@@ -554,6 +603,11 @@ def main(args: List[str] = sys.argv) -> None:
         parsed_opts.z_index = parse_z_index(cli_opts.z_index)
     except Exception:
         raise SystemExit(f'Not a valid z-index specification: {cli_opts.z_index}')
+    if cli_opts.background == 'terminal':
+        bg = query_terminal_background(cli_opts.detection_timeout)
+        if bg is None:
+            raise SystemExit('Failed to query the terminal for its background color')
+        cli_opts.background = bg
     if cli_opts.background != 'none':
         ra = to_color(cli_opts.background)
         if ra is None:
@@ -562,19 +616,21 @@ def main(args: List[str] = sys.argv) -> None:
     parsed_opts.flip = cli_opts.mirror in ('both', 'vertical')
     parsed_opts.flop = cli_opts.mirror in ('both', 'horizontal')
 
+    passthrough_mode = cli_opts.passthrough
     if cli_opts.detect_support:
         if not detect_support(wait_for=cli_opts.detection_timeout, silent=True):
             raise SystemExit(1)
         print('file' if can_transfer_with_files else 'stream', end='', file=sys.stderr)
         return
-    if cli_opts.transfer_mode == 'detect':
+    if cli_opts.transfer_mode == 'detect' and passthrough_mode == 'none':
         if not detect_support(wait_for=cli_opts.detection_timeout, silent=cli_opts.silent):
             raise SystemExit('This terminal emulator does not support the graphics protocol, use a terminal emulator such as kitty that does support it')
     else:
         can_transfer_with_files = cli_opts.transfer_mode == 'file'
     errors = []
     if cli_opts.clear:
-        sys.stdout.write(clear_images_on_screen(delete_data=True))
+        cc = clear_images_on_screen(delete_data=True).encode('ascii')
+        sys.stdout.buffer.write(tmux_passthrough(cc) if passthrough_mode == 'tmux' else cc)
         if not items:
             return
     if not items:
@@ -0,0 +1,332 @@
+#!/usr/bin/env python
+# License: GPLv3 Copyright: 2022, Kovid Goyal <kovid at kovidgoyal.net>
+
+import json
+import os
+import sys
+from collections import deque
+from typing import (
+    Any, Callable, Deque, Dict, Iterator, List, NamedTuple, Optional, Tuple
+)
+
+from kitty.cli import parse_args
+from kitty.cli_stub import LayoutDesignerCLIOptions
+from kitty.constants import appname, config_dir
+from kitty.fast_data_types import truncate_point_for_length, wcswidth
+from kitty.key_encoding import ALT, CTRL, SHIFT, EventType, KeyEvent
+from kitty.remote_control import create_basic_command, encode_send
+from kitty.typing import ScreenSize
+
+from ..tui.handler import Handler
+from ..tui.loop import Loop
+from ..tui.operations import styled
+
+OPTIONS = r'''
+--session-file
+default=splits-session.conf
+The file to save the :ref:`session <sessions>` to when pressing :kbd:`E`.
+Relative paths are relative to the kitty config directory.
+
+
+--increment
+type=int
+default=2
+The number of cells to change the size of a window by when resizing.
+'''.format
+help_text = '''\
+Show the arrangement of the windows in the current tab, when using the splits
+layout, and move, rotate and resize them using the keyboard. The changes are
+applied immediately using remote control, so it must be enabled. The resulting
+layout can be saved as a session file.
+'''
+usage = ''
+directions = {'LEFT': 'left', 'RIGHT': 'right', 'UP': 'top', 'DOWN': 'bottom', 'h': 'left', 'l': 'right', 'k': 'top', 'j': 'bottom'}
+
+
+class Rect(NamedTuple):
+    left: int
+    top: int
+    width: int
+    height: int
+
+
+def layout_rects(node: Any, left: int, top: int, width: int, height: int) -> Iterator[Tuple[int, Rect]]:
+    if node is None:
+        return
+    if isinstance(node, int):
+        yield node, Rect(left, top, width, height)
+        return
+    one, two = node.get('one'), node.get('two')
+    if one is None or two is None:
+        yield from layout_rects(two if one is None else one, left, top, width, height)
+        return
+    if node['horizontal']:
+        w1 = max(1, min(width - 1, round(width * node['bias'])))
+        yield from layout_rects(one, left, top, w1, height)
+        yield from layout_rects(two, left + w1, top, width - w1, height)
+    else:
+        h1 = max(1, min(height - 1, round(height * node['bias'])))
+        yield from layout_rects(one, left, top, width, h1)
+        yield from layout_rects(two, left, top + h1, width, height - h1)
+
+
+def neighbor(rects: Dict[int, Rect], current: int, direction: str) -> Optional[int]:
+    c = rects[current]
+    cx, cy = c.left + c.width / 2, c.top + c.height / 2
+    ans: Optional[int] = None
+    min_dist = 0.
+    for q, r in rects.items():
+        if direction == 'left':
+            ok = r.left + r.width <= c.left
+        elif direction == 'right':
+            ok = r.left >= c.left + c.width
+        elif direction == 'top':
+            ok = r.top + r.height <= c.top
+        else:
+            ok = r.top >= c.top + c.height
+        if ok:
+            dist = abs(r.left + r.width / 2 - cx) + abs(r.top + r.height / 2 - cy)
+            if ans is None or dist < min_dist:
+                ans, min_dist = q, dist
+    return ans
+
+
+def truncate(text: str, width: int) -> str:
+    if wcswidth(text) <= width:
+        return text
+    return text[:truncate_point_for_length(text, max(0, width - 1))] + '…'
+
+
+class LayoutDesigner(Handler):
+
+    print_on_fail: Optional[str] = None
+
+    def __init__(self, opts: LayoutDesignerCLIOptions) -> None:
+        self.opts = opts
+        self.window_id = int(os.environ.get('KITTY_WINDOW_ID', '0'))
+        self.pending: Deque[Callable[[Any], None]] = deque()
+        self.tab: Optional[Dict[str, Any]] = None
+        self.titles: Dict[int, str] = {}
+        self.main_windows: Dict[int, int] = {}
+        self.rects: Dict[int, Rect] = {}
+        self.selected_group = 0
+        self.message = ''
+
+    def initialize(self) -> None:
+        self.cmd.set_cursor_visible(False)
+        self.cmd.set_line_wrapping(False)
+        self.draw_screen()
+        self.refresh()
+
+    def finalize(self) -> None:
+        self.cmd.set_cursor_visible(True)
+        self.cmd.set_line_wrapping(True)
+
+    def send(self, name: str, payload: Dict[str, Any], callback: Optional[Callable[[Any], None]] = None) -> None:
+        self.write(encode_send(create_basic_command(name, payload, no_response=callback is None)))
+        if callback is not None:
+            self.pending.append(callback)
+
+    def on_kitty_cmd_response(self, response: Dict[str, Any]) -> None:
+        callback = self.pending.popleft() if self.pending else None
+        if not response.get('ok'):
+            err = response['error']
+            if response.get('tb'):
+                err += '\n' + response['tb']
+            if self.tab is None:
+                self.print_on_fail = err
+                self.quit_loop(1)
+                return
+            self.message = err
+            self.draw_screen()
+            return
+        if callback is not None:
+            callback(response.get('data'))
+
+    def refresh(self) -> None:
+        self.send('ls', {'all_env_vars': False}, self.on_ls)
+
+    def on_ls(self, data: str) -> None:
+        self.tab = None
+        for os_window in json.loads(data):
+            for tab in os_window['tabs']:
+                if any(w['is_self'] for w in tab['windows']):
+                    self.tab = tab
+        if self.tab is None:
+            self.print_on_fail = 'Could not find the tab this kitten is running in'
+            self.quit_loop(1)
+            return
+        titles = {w['id']: w['title'] for w in self.tab['windows']}
+        self.titles, self.main_windows = {}, {}
+        for g in self.tab['groups']:
+            if g['windows']:
+                self.main_windows[g['id']] = g['windows'][0]
+                self.titles[g['id']] = titles.get(g['windows'][0], '')
+                if self.window_id in g['windows'] and not self.selected_group:
+                    self.selected_group = g['id']
+        if self.selected_group not in self.main_windows:
+            self.selected_group = next(iter(self.main_windows), 0)
+        self.request_redraw()
+
+    @property
+    def is_splits(self) -> bool:
+        return self.tab is not None and self.tab['layout'] == 'splits'
+
+    def act_on_selected(self, action: str) -> None:
+        wid = self.main_windows.get(self.selected_group)
+        if wid is None:
+            return
+        # layout actions operate on the active window, so focus the selected
+        # window temporarily
+        self.send('focus-window', {'match': f'id:{wid}'})
+        self.send('action', {'action': action, 'match': f'id:{wid}'})
+        self.send('focus-window', {'match': f'id:{self.window_id}'})
+        self.message = ''
+        self.refresh()
+
+    def resize_selected(self, axis: str, increment: int = 0) -> None:
+        wid = self.main_windows.get(self.selected_group)
+        if wid is None:
+            return
+        self.send('resize-window', {'match': f'id:{wid}', 'axis': axis, 'increment': increment or 1, 'self': False}, lambda data: self.refresh())
+
+    def export_session(self) -> None:
+        if self.tab is None:
+            return
+        path = os.path.join(config_dir, os.path.expanduser(self.opts.session_file))
+
+        def save(data: str) -> None:
+            with open(path, 'w') as f:
+                f.write(data + '\n')
+            self.message = f'Session saved to: {path}'
+            self.draw_screen()
+
+        self.send('export-session', {'match_tab': f'id:{self.tab["id"]}'}, save)
+
+    def on_text(self, text: str, in_bracketed_paste: bool = False) -> None:
+        if text in ('q', 'Q'):
+            self.quit_loop(0)
+        elif not self.is_splits:
+            if text in ('l', 'L'):
+                self.send('action', {'action': 'goto_layout splits', 'match': f'id:{self.window_id}'})
+                self.refresh()
+        elif text in directions:
+            self.select(directions[text])
+        elif text.lower() in directions:
+            self.act_on_selected(f'move_window {directions[text.lower()]}')
+        elif text == 'r':
+            self.act_on_selected('layout_action rotate')
+        elif text == 'R':
+            self.act_on_selected('layout_action rotate 180')
+        elif text == '=':
+            self.resize_selected('reset')
+        elif text in ('e', 'E'):
+            self.export_session()
+
+    def select(self, direction: str) -> None:
+        if self.selected_group in self.rects:
+            q = neighbor(self.rects, self.selected_group, direction)
+            if q is not None:
+                self.selected_group = q
+                self.draw_screen()
+
+    def on_key(self, key_event: KeyEvent) -> None:
+        if key_event.type is EventType.RELEASE:
+            return
+        if key_event.matches('esc'):
+            self.quit_loop(0)
+            return
+        if not self.is_splits or key_event.key not in ('LEFT', 'RIGHT', 'UP', 'DOWN'):
+            return
+        direction = directions[key_event.key]
+        mods = key_event.mods_without_locks
+        if not mods:
+            self.select(direction)
+        elif mods == SHIFT:
+            self.act_on_selected(f'move_window {direction}')
+        elif mods in (CTRL, ALT):
+            increment = self.opts.increment * (-1 if direction in ('left', 'bottom') else 1)
+            self.resize_selected('horizontal' if direction in ('left', 'right') else 'vertical', increment)
+
+    def draw_box(self, group_id: int, r: Rect) -> None:
+        if r.width < 2 or r.height < 2:
+            return
+        selected = group_id == self.selected_group
+        color = 'green' if selected else 'gray'
+        inner = r.width - 2
+
+        def draw(x: int, y: int, text: str) -> None:
+            self.cmd.set_cursor_position(x, y)
+            self.write(styled(text, fg=color, bold=selected))
+
+        draw(r.left, r.top, '┌' + '─' * inner + '┐')
+        for y in range(r.top + 1, r.top + r.height - 1):
+            draw(r.left, y, '│')
+            draw(r.left + r.width - 1, y, '│')
+        draw(r.left, r.top + r.height - 1, '└' + '─' * inner + '┘')
+        if r.height > 2 and inner > 0:
+            title = truncate(self.titles.get(group_id, ''), inner)
+            draw(r.left + 1 + (inner - wcswidth(title)) // 2, r.top + (r.height - 1) // 2, title)
+
+    def draw_screen(self) -> None:
+        self.cmd.clear_screen()
+        self.print(styled('Splits layout designer', bold=True))
+        if self.tab is None:
+            self.print('Loading...')
+            return
+        if not self.is_splits:
+            self.print(f'The current layout is {styled(self.tab["layout"], fg="yellow")}, not splits.')
+            self.print(f'Press {styled("L", fg="green")} to switch to the splits layout or {styled("Q", fg="red")} to quit.')
+            return
+        rows, cols = self.screen_size.rows, self.screen_size.cols
+        self.rects = dict(layout_rects(self.tab['layout_state'].get('pairs'), 0, 2, cols, max(2, rows - 6)))
+        for group_id, r in self.rects.items():
+            self.draw_box(group_id, r)
+        self.cmd.set_cursor_position(0, rows - 3)
+        self.print(
+            styled('Arrows', fg='green'), 'select',
+            styled('Shift+Arrows', fg='green'), 'move',
+            styled('Ctrl+Arrows', fg='green'), 'resize',
+            styled('R', fg='green'), 'rotate',
+            styled('=', fg='green'), 'reset sizes',
+            styled('E', fg='green'), 'save session',
+            styled('Q', fg='red'), 'quit')
+        if self.message:
+            self.write(truncate(self.message, cols))
+
+    def on_resize(self, screen_size: ScreenSize) -> None:
+        super().on_resize(screen_size)
+        self.draw_screen()
+
+    def on_interrupt(self) -> None:
+        self.quit_loop(1)
+
+    def on_eot(self) -> None:
+        self.quit_loop(1)
+
+
+def main(args: List[str]) -> None:
+    try:
+        cli_opts, items = parse_args(
+            args[1:], OPTIONS, usage, help_text, f'{appname} +kitten layout_designer', result_class=LayoutDesignerCLIOptions)
+    except SystemExit as e:
+        if e.code != 0:
+            print(e.args[0], file=sys.stderr)
+            input('Press Enter to quit')
+        return
+    loop = Loop()
+    handler = LayoutDesigner(cli_opts)
+    loop.loop(handler)
+    if handler.print_on_fail:
+        print(handler.print_on_fail, file=sys.stderr)
+        input('Press Enter to quit')
+    raise SystemExit(loop.return_code)
+
+
+if __name__ == '__main__':
+    main(sys.argv)
+elif __name__ == '__doc__':
+    cd = sys.cli_docs  # type: ignore
+    cd['usage'] = usage
+    cd['options'] = OPTIONS
+    cd['help_text'] = help_text
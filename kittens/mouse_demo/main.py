@@ -2,12 +2,19 @@
 # License: GPLv3 Copyright: 2021, Kovid Goyal <kovid at kovidgoyal.net>
 
 import sys
-from typing import List, Optional
+from collections import deque
+from typing import Deque, List, Optional
 
 from ..show_key.kitty_mode import format_mods
 from ..tui.handler import Handler
 from ..tui.loop import Loop, MouseEvent
-from ..tui.operations import MouseTracking
+from ..tui.operations import Mode, MouseTracking
+
+tracking_modes = {
+    MouseTracking.buttons_only: Mode.MOUSE_BUTTON_TRACKING,
+    MouseTracking.buttons_and_drag: Mode.MOUSE_MOTION_TRACKING,
+    MouseTracking.full: Mode.MOUSE_MOVE_TRACKING,
+}
 
 
 class Mouse(Handler):
@@ -15,6 +22,23 @@ class Mouse(Handler):
 
     def __init__(self) -> None:
         self.current_mouse_event: Optional[MouseEvent] = None
+        self.event_log: Deque[str] = deque(maxlen=256)
+
+    def set_tracking_mode(self, mt: MouseTracking) -> None:
+        for mode in tracking_modes.values():
+            self.cmd.reset_mode(mode)
+        self.mouse_tracking = mt
+        self.cmd.set_mode(tracking_modes[mt])
+        self.event_log.clear()
+        self.current_mouse_event = None
+        self.draw_screen()
+
+    def on_text(self, text: str, in_bracketed_paste: bool = False) -> None:
+        if text == ' ':
+            modes = list(tracking_modes)
+            self.set_tracking_mode(modes[(modes.index(self.mouse_tracking) + 1) % len(modes)])
+        elif text == 'q':
+            self.quit_loop(0)
 
     def initialize(self) -> None:
         self.cmd.set_cursor_visible(False)
@@ -25,11 +49,19 @@ def finalize(self) -> None:
 
     def on_mouse_event(self, ev: MouseEvent) -> None:
         self.current_mouse_event = ev
-        self.draw_screen()
+        desc = f'{ev.type.name} at cell: {ev.cell_x}, {ev.cell_y} pixel: {ev.pixel_x}, {ev.pixel_y}'
+        if ev.buttons:
+            desc += f' buttons: {ev.buttons}'
+        if ev.mods:
+            desc += f' mods: {format_mods(ev.mods)}'
+        self.event_log.append(desc)
+        self.request_redraw()
 
     @Handler.atomic_update
     def draw_screen(self) -> None:
         self.cmd.clear_screen()
+        self.print(f'Tracking mode: {self.mouse_tracking.name}. Press space to change it and q to quit.')
+        self.print()
         ev = self.current_mouse_event
         if ev is None:
             self.print('Move the mouse or click to see mouse events')
@@ -41,6 +73,12 @@ def draw_screen(self) -> None:
             self.print(ev.buttons)
         if ev.mods:
             self.print(f'Modifiers: {format_mods(ev.mods)}')
+        available = self.screen_size.rows - 8 - bool(ev.buttons) - bool(ev.mods)
+        if available > 0:
+            self.print()
+            self.print('Recent events:')
+            for line in list(self.event_log)[-available:]:
+                self.print(line[:self.screen_size.cols])
 
     def on_interrupt(self) -> None:
         self.quit_loop(0)
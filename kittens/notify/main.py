@@ -0,0 +1,118 @@
+#!/usr/bin/env python
+# License: GPLv3 Copyright: 2022, Kovid Goyal <kovid at kovidgoyal.net>
+
+import re
+import secrets
+import sys
+from base64 import standard_b64encode
+from typing import List
+
+from kitty.cli import parse_args
+from kitty.cli_stub import NotifyCLIOptions
+from kitty.constants import appname
+from kitty.utils import TTYIO
+
+OPTIONS = r'''
+--identifier -i
+An identifier for this notification. Only the characters :code:`a-zA-Z0-9-_+.`
+are allowed. If not specified and :option:`--wait-for-activation` is used, a
+random identifier is generated.
+
+
+--wait-for-activation -w
+type=bool-set
+Wait till the notification is activated (clicked on) by the user, then print
+the notification identifier and exit. Exits with a non-zero exit code if the
+notification is not activated within :option:`--timeout` seconds.
+
+
+--timeout
+type=float
+default=0
+The maximum amount of time (in seconds) to wait for the notification to be
+activated. Zero or negative values mean wait forever.
+
+
+--no-focus
+type=bool-set
+Do not focus the window from which the notification was sent when it is
+activated.
+'''.format
+help_text = '''\
+Send a desktop notification using the terminal. The notification is sent using
+the :doc:`desktop notifications protocol </desktop-notifications>`, so it works
+over SSH as well. The first argument is the title of the notification, the
+remaining arguments, if any, are joined to form the body.
+'''
+usage = 'title [body ...]'
+identifier_pat = re.compile(r'[^a-zA-Z0-9-_+.]+')
+
+
+def encode(text: str) -> str:
+    return standard_b64encode(text.encode('utf-8')).decode('ascii')
+
+
+def notification_escape_codes(identifier: str, title: str, body: str, actions: str) -> List[str]:
+    metadata = f'i={identifier}:e=1'
+    ans = []
+    if actions:
+        metadata += f':a={actions}'
+    ans.append(f'\x1b]99;{metadata}:d={0 if body else 1}:p=title;{encode(title)}\x1b\\')
+    if body:
+        ans.append(f'\x1b]99;i={identifier}:e=1:d=1:p=body;{encode(body)}\x1b\\')
+    return ans
+
+
+def wait_for_activation(ttyio: TTYIO, identifier: str, timeout: float) -> bool:
+    pat = re.compile(b'\x1b]99;i=' + re.escape(identifier.encode('ascii')) + b';\x1b\\\\')
+    received = b''
+    activated = False
+
+    def more_needed(data: bytes) -> bool:
+        nonlocal received, activated
+        if b'\x03' in data:
+            raise KeyboardInterrupt()
+        received += data
+        if pat.search(received) is not None:
+            activated = True
+            return False
+        return True
+
+    ttyio.recv(more_needed, timeout=timeout if timeout > 0 else float('inf'))
+    return activated
+
+
+def main(args: List[str] = sys.argv) -> None:
+    cli_opts, items = parse_args(
+        args[1:], OPTIONS, usage, help_text, f'{appname} +kitten notify', result_class=NotifyCLIOptions)
+    if not items:
+        raise SystemExit('Must specify a title for the notification')
+    title, body = items[0], ' '.join(items[1:])
+    identifier = identifier_pat.sub('', cli_opts.identifier or '')
+    if not identifier and cli_opts.wait_for_activation:
+        identifier = secrets.token_hex(8)
+    identifier = identifier or '0'
+    actions = []
+    if cli_opts.no_focus:
+        actions.append('-focus')
+    if cli_opts.wait_for_activation:
+        actions.append('report')
+    with TTYIO() as ttyio:
+        ttyio.send(notification_escape_codes(identifier, title, body, ','.join(actions)))
+        if cli_opts.wait_for_activation:
+            try:
+                activated = wait_for_activation(ttyio, identifier, cli_opts.timeout)
+            except KeyboardInterrupt:
+                raise SystemExit(1)
+            if not activated:
+                raise SystemExit(1)
+            print(identifier)
+
+
+if __name__ == '__main__':
+    main()
+elif __name__ == '__doc__':
+    cd = sys.cli_docs  # type: ignore
+    cd['usage'] = usage
+    cd['options'] = OPTIONS
+    cd['help_text'] = help_text
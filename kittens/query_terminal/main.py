@@ -201,6 +201,14 @@ def options_spec() -> str:
 default=10
 The amount of time (in seconds) to wait for a response from the terminal, after
 querying it.
+
+
+--format
+default=text
+choices=text,json
+The format in which to output the results. The :code:`json` format outputs a
+single JSON object mapping query names to their values, with :code:`null` for
+unsupported queries, suitable for consumption by scripts.
 '''
 
 
@@ -211,12 +219,13 @@ def options_spec() -> str:
 wait for a response from the terminal. You can control the maximum wait time via
 the :code:`--wait-for` option.
 
-The output is lines of the form::
+By default, the output is lines of the form::
 
     query: data
 
 If a particular :italic:`query` is unsupported by the running kitty version, the
-:italic:`data` will be blank.
+:italic:`data` will be blank. Use :code:`--format=json` to get the results as a
+JSON object instead.
 
 Note that when calling this from another program, be very careful not to perform
 any I/O on the terminal device until this kitten exits.
@@ -247,7 +256,12 @@ def main(args: List[str] = sys.argv) -> None:
         if extra:
             raise SystemExit(f'Unknown queries: {", ".join(extra)}')
 
-    for key, val in do_queries(queries, cli_opts).items():
+    results = do_queries(queries, cli_opts)
+    if cli_opts.format == 'json':
+        import json
+        print(json.dumps({k: v or None for k, v in results.items()}, indent=2, sort_keys=True))
+        return
+    for key, val in results.items():
         print(f'{key}:', val)
 
 
@@ -0,0 +1,100 @@
+#!/usr/bin/env python
+# License: GPLv3 Copyright: 2022, Kovid Goyal <kovid at kovidgoyal.net>
+
+import sys
+from typing import List
+
+from kitty.cli import parse_args
+from kitty.cli_stub import ResetTerminalCLIOptions
+from kitty.constants import appname
+
+from ..tui.operations import Mode, clear_screen, reset_mode, set_mode
+
+OPTIONS = r'''
+--hard
+type=bool-set
+Perform a full reset of the terminal (RIS). This resets everything, including
+the screen contents and scrollback, and ignores the other options.
+
+
+--keep-screen
+type=bool-set
+Do not clear the screen and do not switch back to the main screen from the
+alternate screen.
+
+
+--keep-keyboard
+type=bool-set
+Do not reset the keyboard mode. By default all keyboard modes pushed by
+programs using the :doc:`keyboard protocol </keyboard-protocol>` are popped.
+
+
+--keep-mouse
+type=bool-set
+Do not turn off mouse tracking.
+
+
+--keep-graphics
+type=bool-set
+Do not delete images displayed using the :doc:`graphics protocol </graphics-protocol>`.
+
+
+--keep-colors
+type=bool-set
+Do not reset colors changed by programs back to their configured values.
+'''.format
+help_text = '''\
+Reset the state of the terminal, for recovering after a misbehaving program
+leaves it in a bad state, for example, with mouse tracking turned on, keys
+sending strange escape codes or colors changed. By default, a soft reset is
+performed along with resetting all modes, which leaves the scrollback intact.
+Use the options to control what is reset.
+'''
+usage = ''
+
+
+def reset_codes(opts: ResetTerminalCLIOptions) -> str:
+    if opts.hard:
+        return '\033c'
+    ans = ['\033[!p']  # DECSTR soft reset
+    if not opts.keep_screen:
+        ans.append(reset_mode(Mode.ALTERNATE_SCREEN))
+    ans.extend(reset_mode(m) for m in (
+        Mode.LNM, Mode.IRM, Mode.DECKM, Mode.DECSCNM, Mode.DECOM, Mode.BRACKETED_PASTE, Mode.FOCUS_TRACKING,
+        Mode.PENDING_UPDATE))
+    ans.extend(set_mode(m) for m in (Mode.DECAWM, Mode.DECARM, Mode.DECTCEM))
+    ans.append('\033>')  # normal keypad mode
+    ans.append('\033[r')  # reset scroll region
+    ans.append('\033[m')
+    if not opts.keep_mouse:
+        ans.extend(reset_mode(m) for m in (
+            Mode.MOUSE_BUTTON_TRACKING, Mode.MOUSE_MOTION_TRACKING, Mode.MOUSE_MOVE_TRACKING, Mode.MOUSE_UTF8_MODE,
+            Mode.MOUSE_SGR_MODE, Mode.MOUSE_URXVT_MODE, Mode.MOUSE_SGR_PIXEL_MODE))
+    if not opts.keep_keyboard:
+        ans.append('\033[<99u')  # pop all keyboard modes
+        ans.append('\033[=0;1u')  # and clear the flags of the base mode
+    if not opts.keep_graphics:
+        ans.append('\033_Ga=d,d=A,q=2\033\\')
+    if not opts.keep_colors:
+        ans.append('\033]104\033\\\033]110\033\\\033]111\033\\\033]112\033\\')
+    if not opts.keep_screen:
+        ans.append(clear_screen())
+    return ''.join(ans)
+
+
+def main(args: List[str] = sys.argv) -> None:
+    cli_opts, items = parse_args(
+        args[1:], OPTIONS, usage, help_text, f'{appname} +kitten reset_terminal', result_class=ResetTerminalCLIOptions)
+    if items:
+        raise SystemExit('This kitten does not accept any arguments')
+    sys.stdout.write(reset_codes(cli_opts))
+    sys.stdout.flush()
+
+
+if __name__ == '__main__':
+    main()
+elif __name__ == '__doc__':
+    cd = sys.cli_docs  # type: ignore
+    cd['usage'] = usage
+    cd['options'] = OPTIONS
+    cd['help_text'] = help_text
@@ -18,6 +18,7 @@
 from ..tui.operations import styled
 
 global_opts = RCOptions()
+arrow_keys = ('LEFT', 'RIGHT', 'UP', 'DOWN')
 
 
 class Resize(Handler):
@@ -75,6 +76,10 @@ def on_key(self, key_event: KeyEvent) -> None:
             return
         if key_event.key in ('w', 'n', 't', 's') and key_event.mods_without_locks == CTRL:
             self.do_window_resize(is_decrease=key_event.key in 'ns', is_horizontal=key_event.key in 'wn', multiplier=2)
+        elif key_event.key in arrow_keys and key_event.mods_without_locks in (0, CTRL):
+            self.do_window_resize(
+                is_decrease=key_event.key in ('LEFT', 'DOWN'), is_horizontal=key_event.key in ('LEFT', 'RIGHT'),
+                multiplier=2 if key_event.mods_without_locks == CTRL else 1)
 
     def on_resize(self, new_size: ScreenSize) -> None:
         self.draw_screen()
@@ -91,6 +96,7 @@ def draw_screen(self) -> None:
         print('  {}horter'.format(styled('S', fg='green')))
         print('  {}eset'.format(styled('R', fg='red')))
         print()
+        print('The {} keys can also be used to resize'.format(styled('arrow', italic=True)))
         print('Press {} to quit resize mode'.format(styled('Esc', italic=True)))
         print('Hold down {} to double step size'.format(styled('Ctrl', italic=True)))
         print()
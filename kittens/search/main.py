@@ -0,0 +1,195 @@
+#!/usr/bin/env python
+# License: GPLv3 Copyright: 2022, Kovid Goyal <kovid at kovidgoyal.net>
+
+import os
+import re
+import sys
+from typing import Any, Dict, List, Optional
+
+from kitty.cli import parse_args
+from kitty.cli_stub import SearchCLIOptions
+from kitty.constants import appname
+from kitty.key_encoding import EventType
+from kitty.remote_control import create_basic_command, encode_send
+from kitty.typing import KeyEventType, ScreenSize
+
+from ..tui.handler import Handler
+from ..tui.line_edit import LineEdit
+from ..tui.loop import Loop
+from ..tui.operations import styled
+
+OPTIONS = r'''
+--mode
+default=itext
+choices=itext,text,regex
+How to match the query. :code:`itext` matches the text ignoring case,
+:code:`text` matches it exactly and :code:`regex` treats it as a Python regular
+expression. Press :kbd:`Tab` in the kitten to cycle between modes.
+
+
+--keep-marker
+type=bool-set
+Leave the matches highlighted when the kitten is quit with :kbd:`Esc`. By
+default, the highlighting is only kept when quitting with :kbd:`Enter`.
+'''.format
+help_text = '''\
+Interactively search the scrollback of a kitty window. As you type, matches are
+highlighted in the window and it is scrolled to the most recent match. Use the
+up and down arrow keys to jump between matches, :kbd:`Enter` to quit keeping the
+matches highlighted and :kbd:`Esc` to quit and clear them. The window to search
+is specified by its id, when run as an overlay with the :code:`kitten` action,
+the overlaid window is searched. Requires remote control to be enabled.
+'''
+usage = '[window id]'
+modes = ('itext', 'text', 'regex')
+
+
+class Search(Handler):
+
+    print_on_fail: Optional[str] = None
+
+    def __init__(self, opts: SearchCLIOptions, match: str) -> None:
+        self.opts = opts
+        self.match = match
+        self.mode = opts.mode
+        self.line_edit = LineEdit()
+        self.error = ''
+        self.has_marker = False
+
+    @property
+    def query(self) -> str:
+        return self.line_edit.current_input
+
+    def initialize(self) -> None:
+        self.draw_screen()
+
+    def send_command(self, name: str, payload: Dict[str, Any]) -> None:
+        payload['match'] = self.match
+        self.write(encode_send(create_basic_command(name, payload)))
+
+    def perform_action(self, action: str) -> None:
+        self.send_command('action', {'action': action})
+
+    def remove_marker(self) -> None:
+        if self.has_marker:
+            self.send_command('remove-marker', {'self': False})
+            self.has_marker = False
+
+    def update_marker(self) -> None:
+        self.error = ''
+        query = self.query
+        if self.mode == 'regex' and query:
+            try:
+                re.compile(query)
+            except re.error as err:
+                self.error = str(err)
+                return
+        self.perform_action('scroll_end')
+        if not query:
+            self.remove_marker()
+            return
+        self.send_command('create-marker', {'self': False, 'marker_spec': [self.mode, '1', query]})
+        self.has_marker = True
+        self.perform_action('scroll_to_mark prev')
+
+    def on_kitty_cmd_response(self, response: Dict[str, Any]) -> None:
+        if not response.get('ok'):
+            err = response['error']
+            if response.get('tb'):
+                err += '\n' + response['tb']
+            self.print_on_fail = err
+            self.quit_loop(1)
+
+    def draw_screen(self) -> None:
+        self.cmd.clear_screen()
+        self.print(
+            styled('Up/Down', fg='yellow'), 'jump to matches',
+            styled('Tab', fg='yellow'), 'change mode',
+            styled('Enter', fg='yellow'), 'done',
+            styled('Esc', fg='yellow'), 'cancel')
+        if self.error:
+            self.print(styled(self.error, fg='red'))
+        else:
+            self.print('')
+        self.line_edit.write(self.write, styled(f'{self.mode}> ', fg='green'), screen_cols=self.screen_size.cols)
+
+    def on_resize(self, screen_size: ScreenSize) -> None:
+        super().on_resize(screen_size)
+        self.draw_screen()
+
+    def on_text(self, text: str, in_bracketed_paste: bool = False) -> None:
+        self.line_edit.on_text(text, in_bracketed_paste)
+        self.update_marker()
+        self.draw_screen()
+
+    def on_key(self, key_event: KeyEventType) -> None:
+        if key_event.type is EventType.RELEASE:
+            return
+        if key_event.matches('esc'):
+            if not self.opts.keep_marker:
+                self.remove_marker()
+                self.perform_action('scroll_end')
+            self.quit_loop(0)
+            return
+        if key_event.matches('enter'):
+            self.quit_loop(0)
+            return
+        if key_event.matches('up') or key_event.matches('ctrl+p'):
+            if self.has_marker:
+                self.perform_action('scroll_to_mark prev')
+            return
+        if key_event.matches('down') or key_event.matches('ctrl+n'):
+            if self.has_marker:
+                self.perform_action('scroll_to_mark next')
+            return
+        if key_event.matches('tab'):
+            self.mode = modes[(modes.index(self.mode) + 1) % len(modes)]
+            self.update_marker()
+            self.draw_screen()
+            return
+        before = self.query
+        if self.line_edit.on_key(key_event):
+            if self.query != before:
+                self.update_marker()
+            self.draw_screen()
+
+    def on_interrupt(self) -> None:
+        self.remove_marker()
+        self.quit_loop(1)
+
+    def on_eot(self) -> None:
+        self.on_interrupt()
+
+
+def main(args: List[str]) -> None:
+    try:
+        cli_opts, items = parse_args(args[1:], OPTIONS, usage, help_text, f'{appname} +kitten search', result_class=SearchCLIOptions)
+        if items:
+            if not items[0].isdigit():
+                raise SystemExit(f'Not a valid window id: {items[0]}')
+            match = f'id:{items[0]}'
+        elif os.environ.get('KITTY_CHILD_PID'):
+            match = f'pid:{os.environ["KITTY_CHILD_PID"]}'
+        else:
+            raise SystemExit('Must specify the id of the window to search')
+    except SystemExit as e:
+        if e.code != 0:
+            print(e.args[0], file=sys.stderr)
+            input('Press Enter to quit')
+        return
+    loop = Loop()
+    handler = Search(cli_opts, match)
+    loop.loop(handler)
+    if handler.print_on_fail:
+        print(handler.print_on_fail, file=sys.stderr)
+        input('Press Enter to quit')
+    raise SystemExit(loop.return_code)
+
+
+if __name__ == '__main__':
+    main(sys.argv)
+elif __name__ == '__doc__':
+    cd = sys.cli_docs  # type: ignore
+    cd['usage'] = usage
+    cd['options'] = OPTIONS
+    cd['help_text'] = help_text
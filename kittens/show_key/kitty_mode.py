@@ -34,10 +34,14 @@ def format_mods(mods: int) -> str:
 class KeysHandler(Handler):
 
     def initialize(self) -> None:
+        self.cmd.save_window_title()
         self.cmd.set_window_title('kitty extended keyboard protocol demo')
         self.cmd.set_cursor_visible(False)
         self.print('Press any keys - Ctrl+C or Ctrl+D will terminate')
 
+    def finalize(self) -> None:
+        self.cmd.restore_window_title()
+
     def on_key_event(self, key_event: KeyEvent, in_bracketed_paste: bool = False) -> None:
         etype = {
             PRESS: 'PRESS',
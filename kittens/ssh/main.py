@@ -35,8 +35,8 @@
 from kitty.shm import SharedMemory
 from kitty.types import run_once
 from kitty.utils import (
-    SSHConnectionData, expandvars, resolve_abs_or_config_path,
-    set_echo as turn_off_echo
+    SSHConnectionData, cleanup_stale_ssh_control_masters, expandvars,
+    resolve_abs_or_config_path, set_echo as turn_off_echo
 )
 
 from ..tui.operations import (
@@ -692,6 +692,7 @@ def run_ssh(ssh_args: List[str], server_args: List[str], found_extra_args: Tuple
         overrides.insert(0, f'hostname {uname}@{hostname_for_match}')
     host_opts = init_config(hostname_for_match, uname, overrides)
     if host_opts.share_connections:
+        cleanup_stale_ssh_control_masters()
         cmd[insertion_point:insertion_point] = connection_sharing_args(int(os.environ['KITTY_PID']))
     use_kitty_askpass = host_opts.askpass == 'native' or (host_opts.askpass == 'unless-set' and 'SSH_ASKPASS' not in os.environ)
     need_to_request_data = True
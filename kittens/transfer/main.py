@@ -57,6 +57,14 @@ def option_text() -> str:
 update it to match the file on the sending side, potentially saving lots of
 bandwidth and also automatically resuming partial transfers. Note that this will
 actually degrade performance on fast links with small files, so use with care.
+
+
+--compress
+default=auto
+choices=auto,always,never
+Whether to compress file data during transmission. In :code:`auto` mode, files
+that are small, or that are already compressed, as detected by their extension
+and, when sending, by their contents, are transmitted without compression.
 '''
 
 
@@ -46,7 +46,7 @@ class State(NameReprEnum):
 
 class File:
 
-    def __init__(self, ftc: FileTransmissionCommand):
+    def __init__(self, ftc: FileTransmissionCommand, compress: str = 'auto'):
         self.expected_size = ftc.size
         self.expect_diff = False
         self.transmit_started_at = self.done_at = 0.
@@ -64,7 +64,8 @@ def __init__(self, ftc: FileTransmissionCommand):
         self.parent = ftc.parent
         self.expanded_local_path = ''
         self.file_id = str(next(file_counter))
-        compression_capable = self.ftype is FileType.regular and self.expected_size > 4096 and should_be_compressed(self.remote_path)
+        compression_capable = self.ftype is FileType.regular and (
+            compress == 'always' or (compress == 'auto' and self.expected_size > 4096 and should_be_compressed(self.remote_path)))
         self.decompressor: Union[ZlibDecompressor, IdentityDecompressor] = ZlibDecompressor() if compression_capable else IdentityDecompressor()
         self.remote_symlink_value = b''
         self.actual_file: Union[None, PatchFile, IO[bytes]] = None
@@ -227,7 +228,7 @@ class Manager:
 
     def __init__(
         self, request_id: str, spec: List[str], dest: str,
-        bypass: Optional[str] = None, use_rsync: bool = False
+        bypass: Optional[str] = None, use_rsync: bool = False, compress: str = 'auto'
     ):
         self.request_id = request_id
         self.spec = spec
@@ -243,6 +244,7 @@ def __init__(
         self.progress_tracker = ProgressTracker()
         self.transfer_done = False
         self.use_rsync = use_rsync
+        self.compress = compress
 
     @property
     def finish_code(self) -> str:
@@ -359,7 +361,7 @@ def on_file_transfer_response(self, ftc: FileTransmissionCommand) -> str:
                 if fid < 0 or fid >= len(self.spec):
                     return f'Unexpected response from terminal: {ftc}'
                 self.spec_counts[fid] += 1
-                self.files.append(File(ftc))
+                self.files.append(File(ftc, self.compress))
             else:
                 return f'Unexpected response from terminal: {ftc}'
         elif self.state is State.transferring:
@@ -385,7 +387,8 @@ class Receive(Handler):
 
     def __init__(self, cli_opts: TransferCLIOptions, spec: List[str], dest: str = ''):
         self.cli_opts = cli_opts
-        self.manager = Manager(random_id(), spec, dest, bypass=cli_opts.permissions_bypass, use_rsync=cli_opts.transmit_deltas)
+        self.manager = Manager(
+            random_id(), spec, dest, bypass=cli_opts.permissions_bypass, use_rsync=cli_opts.transmit_deltas, compress=cli_opts.compress)
         self.quit_after_write_code: Optional[int] = None
         self.check_paths_printed = False
         self.transmit_started = False
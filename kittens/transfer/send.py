@@ -30,9 +30,9 @@
 from ..tui.utils import human_size
 from .librsync import LoadSignature, delta_for_file
 from .utils import (
-    IdentityCompressor, ZlibCompressor, abspath, expand_home, home_path,
-    print_rsync_stats, random_id, render_progress_in_width, safe_divide,
-    should_be_compressed
+    IdentityCompressor, ZlibCompressor, abspath, expand_home,
+    has_compressed_magic, home_path, print_rsync_stats, random_id,
+    render_progress_in_width, safe_divide, should_be_compressed
 )
 
 debug
@@ -58,7 +58,7 @@ class File:
 
     def __init__(
         self, local_path: str, expanded_local_path: str, file_id: int, stat_result: os.stat_result,
-        remote_base: str, file_type: FileType,
+        remote_base: str, file_type: FileType, compress: str = 'auto',
     ) -> None:
         self.state = FileState.waiting_for_start
         self.local_path = local_path
@@ -76,7 +76,10 @@ def __init__(
         self.stat_result = stat_result
         self.file_type = file_type
         self.rsync_capable = self.file_type is FileType.regular and self.file_size > 4096
-        self.compression_capable = self.file_type is FileType.regular and self.file_size > 4096 and should_be_compressed(self.expanded_local_path)
+        self.compression_capable = self.file_type is FileType.regular and (
+            compress == 'always' or (
+                compress == 'auto' and self.file_size > 4096 and should_be_compressed(self.expanded_local_path) and
+                not has_compressed_magic(self.expanded_local_path)))
         self.remote_final_path = ''
         self.remote_initial_size = -1
         self.err_msg = ''
@@ -158,7 +161,7 @@ def process(cli_opts: TransferCLIOptions, paths: Iterable[str], remote_base: str
         elif stat.S_ISLNK(s.st_mode):
             yield File(x, expanded, next(counter), s, remote_base, FileType.symlink)
         elif stat.S_ISREG(s.st_mode):
-            yield File(x, expanded, next(counter), s, remote_base, FileType.regular)
+            yield File(x, expanded, next(counter), s, remote_base, FileType.regular, cli_opts.compress)
 
 
 def process_mirrored_files(cli_opts: TransferCLIOptions, args: Sequence[str]) -> Iterator[File]:
@@ -457,6 +460,7 @@ def __init__(self, cli_opts: TransferCLIOptions, files: Tuple[File, ...]):
         self.failed_files: List[File] = []
         self.transmit_ok_checked = False
         self.progress_update_call: Optional[TimerHandle] = None
+        self.write_stalled = False
 
     def send_payload(self, payload: str) -> None:
         self.write(self.manager.prefix)
@@ -578,6 +582,10 @@ def on_writing_finished(self) -> None:
         if self.manager.state is SendState.permission_granted and (not self.transmit_started or chunk_transmitted):
             self.asyncio_loop.call_soon(self.loop_tick)
 
+    def on_write_stalled(self, stalled: bool) -> None:
+        self.write_stalled = stalled
+        self.schedule_progress_update()
+
     def loop_tick(self) -> None:
         if self.manager.state is SendState.waiting_for_permission:
             return
@@ -684,7 +692,9 @@ def draw_progress(self) -> None:
                 self.cmd.repeat('─', self.screen_size.width)
             else:
                 af = self.manager.last_progress_file
-                if af is None or af.file_id in self.done_file_ids:
+                if self.write_stalled:
+                    self.print(sc, styled('Waiting for the terminal to accept more data...', fg='yellow'), end='')
+                elif af is None or af.file_id in self.done_file_ids:
                     if self.manager.has_rsync and not self.manager.has_transmitting:
                         self.print(sc, 'Transferring rsync signatures...', end='')
                     else:
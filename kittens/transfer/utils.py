@@ -132,6 +132,38 @@ def should_be_compressed(path: str) -> bool:
     return True
 
 
+compressed_magic_numbers = (
+    b'PK\x03\x04',  # zip and zip based formats
+    b'\x1f\x8b',  # gzip
+    b'BZh',  # bzip2
+    b'\xfd7zXZ\x00',  # xz
+    b'(\xb5/\xfd',  # zstd
+    b'7z\xbc\xaf\x27\x1c',  # 7z
+    b'Rar!\x1a\x07',  # rar
+    b'\x89PNG\r\n\x1a\n',  # png
+    b'\xff\xd8\xff',  # jpeg
+    b'GIF8',  # gif
+    b'OggS',  # ogg
+    b'fLaC',  # flac
+    b'\x1aE\xdf\xa3',  # matroska/webm
+)
+
+
+def has_compressed_magic(path: str) -> bool:
+    try:
+        with open(path, 'rb') as f:
+            header = f.read(16)
+    except OSError:
+        return False
+    if header.startswith(compressed_magic_numbers):
+        return True
+    if header[4:8] == b'ftyp':  # mp4, mov, heic, etc.
+        return True
+    if header.startswith(b'RIFF') and header[8:12] in (b'WEBP', b'AVI '):
+        return True
+    return False
+
+
 def abspath(path: str, use_home: bool = False) -> str:
     base = home_path() if use_home else (_cwd or os.getcwd())
     return os.path.normpath(os.path.join(base, path))
@@ -8,7 +8,7 @@
 from types import TracebackType
 from typing import (
     TYPE_CHECKING, Any, Callable, ContextManager, Deque, Dict, NamedTuple,
-    Optional, Sequence, Type, Union, cast
+    Optional, Sequence, Tuple, Type, Union, cast
 )
 
 from kitty.types import DecoratedFunc, ParsedShortcut
@@ -21,6 +21,7 @@
 from .operations import MouseTracking, pending_update
 
 if TYPE_CHECKING:
+    from kitty.fast_data_types import Color
     from kitty.file_transmission import FileTransmissionCommand
 
 
@@ -43,8 +44,16 @@ class Handler:
     image_manager_class: Optional[Type[ImageManagerType]] = None
     use_alternate_screen = True
     mouse_tracking = MouseTracking.none
+    focus_tracking = False
+    has_focus = True
+    # the background, cursor and selection background colors, for the cursor and selection to be dimmed while unfocused
+    focus_dimmed_colors: Optional[Tuple['Color', 'Color', 'Color']] = None
     terminal_io_ended = False
     overlay_ready_report_needed = False
+    max_redraws_per_second = 60.
+    # seconds without the terminal accepting any written data before
+    # on_write_stalled() is called, zero to disable
+    write_stall_timeout = 5.
 
     def _initialize(
         self,
@@ -64,6 +73,8 @@ def _initialize(
         self.cmd = commander(self)
         self._image_manager = image_manager
         self._button_events: Dict[MouseButton, Deque[ButtonEvent]] = {}
+        self._redraw_pending = False
+        self._last_redraw_at = 0.
 
     @property
     def image_manager(self) -> ImageManagerType:
@@ -110,6 +121,26 @@ def finalize(self) -> None:
     def on_resize(self, screen_size: ScreenSize) -> None:
         self.screen_size = screen_size
 
+    def draw_screen(self) -> None:
+        pass
+
+    def request_redraw(self) -> None:
+        ' Call draw_screen() soon, coalescing multiple requests so that it is called at most max_redraws_per_second times a second '
+        if self._redraw_pending:
+            return
+        self._redraw_pending = True
+        delay = self._last_redraw_at + 1 / self.max_redraws_per_second - monotonic()
+        if delay > 0:
+            self.asyncio_loop.call_later(delay, self._do_redraw)
+        else:
+            self.asyncio_loop.call_soon(self._do_redraw)
+
+    def _do_redraw(self) -> None:
+        self._redraw_pending = False
+        if not self.terminal_io_ended:
+            self._last_redraw_at = monotonic()
+            self.draw_screen()
+
     def quit_loop(self, return_code: Optional[int] = None) -> None:
         self._tui_loop.quit(return_code)
 
@@ -166,6 +197,12 @@ def on_mouse_move(self, mouse_event: MouseEvent) -> None:
     def on_click(self, mouse_event: MouseEvent) -> None:
         pass
 
+    def on_focus_change(self, focused: bool) -> None:
+        ' Called when the window gains or loses focus, if focus_tracking is True. Dims the cursor and selection if focus_dimmed_colors is set '
+        self.has_focus = focused
+        if self.focus_dimmed_colors is not None:
+            self.cmd.set_cursor_and_selection_colors(*self.focus_dimmed_colors, dimmed=not focused)
+
     def on_interrupt(self) -> None:
         pass
 
@@ -175,6 +212,10 @@ def on_eot(self) -> None:
     def on_writing_finished(self) -> None:
         pass
 
+    def on_write_stalled(self, stalled: bool) -> None:
+        ' Called when the terminal stops reading written data, for example because of XOFF, and again when it resumes '
+        pass
+
     def on_kitty_cmd_response(self, response: Dict[str, Any]) -> None:
         pass
 
@@ -1,7 +1,7 @@
 #!/usr/bin/env python3
 # License: GPL v3 Copyright: 2018, Kovid Goyal <kovid at kovidgoyal.net>
 
-from typing import Callable, Tuple
+from typing import Callable, List, Tuple
 
 from kitty.fast_data_types import truncate_point_for_length, wcswidth
 from kitty.key_encoding import EventType, KeyEvent
@@ -11,11 +11,47 @@
 )
 
 
+def shell_token_spans(text: str) -> List[Tuple[int, int]]:
+    ans = []
+    start = -1
+    quote = ''
+    escaped = False
+    for i, ch in enumerate(text):
+        if escaped:
+            escaped = False
+            continue
+        if ch == '\\' and quote != "'":
+            escaped = True
+            if start < 0:
+                start = i
+            continue
+        if quote:
+            if ch == quote:
+                quote = ''
+            continue
+        if ch.isspace():
+            if start > -1:
+                ans.append((start, i))
+                start = -1
+            continue
+        if start < 0:
+            start = i
+        if ch in '"\'':
+            quote = ch
+    if start > -1:
+        ans.append((start, len(text)))
+    return ans
+
+
 class LineEdit:
 
-    def __init__(self, is_password: bool = False) -> None:
+    def __init__(self, is_password: bool = False, word_chars: str = '', shell_tokens: bool = False) -> None:
         self.clear()
         self.is_password = is_password
+        # characters other than letters and digits that are considered part of a word
+        self.word_chars = word_chars
+        # treat quoted strings and command line flags as single words
+        self.shell_tokens = shell_tokens
 
     def clear(self) -> None:
         self.current_input = ''
@@ -124,6 +160,48 @@ def left(self, num: int = 1) -> bool:
     def right(self, num: int = 1) -> bool:
         return self._move_loop(self._right, num)
 
+    def word_spans(self) -> List[Tuple[int, int]]:
+        text = self.current_input
+        if self.shell_tokens:
+            return shell_token_spans(text)
+        ans = []
+        start = -1
+        for i, ch in enumerate(text):
+            if ch.isalnum() or ch in self.word_chars:
+                if start < 0:
+                    start = i
+            elif start > -1:
+                ans.append((start, i))
+                start = -1
+        if start > -1:
+            ans.append((start, len(text)))
+        return ans
+
+    def _move_by_word(self, forward: bool, num: int) -> bool:
+        x = truncate_point_for_length(self.current_input, self.cursor_pos) if self.cursor_pos else 0
+        orig = x
+        spans = self.word_spans()
+        while num > 0:
+            if forward:
+                q = next((end for start, end in spans if end > x), None)
+            else:
+                q = next((start for start, end in reversed(spans) if start < x), None)
+            if q is None:
+                break
+            x = q
+            num -= 1
+        if x == orig:
+            self.pending_bell = True
+            return False
+        self.cursor_pos = wcswidth(self.current_input[:x])
+        return True
+
+    def word_left(self, num: int = 1) -> bool:
+        return self._move_by_word(False, num)
+
+    def word_right(self, num: int = 1) -> bool:
+        return self._move_by_word(True, num)
+
     def home(self) -> bool:
         if self.cursor_pos:
             self.cursor_pos = 0
@@ -154,4 +232,10 @@ def on_key(self, key_event: KeyEvent) -> bool:
         if key_event.matches('right') or key_event.matches('ctrl+f'):
             self.right()
             return True
+        if key_event.matches('ctrl+left') or key_event.matches('alt+b'):
+            self.word_left()
+            return True
+        if key_event.matches('ctrl+right') or key_event.matches('alt+f'):
+            self.word_right()
+            return True
         return False
@@ -4,16 +4,21 @@
 import asyncio
 import codecs
 import io
+import json
 import os
 import re
 import selectors
 import signal
 import sys
 import termios
+from base64 import standard_b64decode, standard_b64encode
 from contextlib import contextmanager, suppress
 from enum import Enum, IntFlag, auto
 from functools import partial
-from typing import Any, Callable, Dict, Generator, List, NamedTuple, Optional
+from time import monotonic
+from typing import (
+    IO, Any, Callable, Dict, Generator, List, NamedTuple, Optional
+)
 
 from kitty.constants import is_macos
 from kitty.fast_data_types import (
@@ -72,17 +77,18 @@ class TermManager:
 
     def __init__(
         self, optional_actions: int = termios.TCSANOW, use_alternate_screen: bool = True,
-        mouse_tracking: MouseTracking = MouseTracking.none
+        mouse_tracking: MouseTracking = MouseTracking.none, focus_tracking: bool = False
     ) -> None:
         self.extra_finalize: Optional[str] = None
         self.optional_actions = optional_actions
         self.use_alternate_screen = use_alternate_screen
         self.mouse_tracking = mouse_tracking
+        self.focus_tracking = focus_tracking
 
     def set_state_for_loop(self, set_raw: bool = True) -> None:
         if set_raw:
             raw_tty(self.tty_fd, self.original_termios)
-        write_all(self.tty_fd, init_state(self.use_alternate_screen, self.mouse_tracking))
+        write_all(self.tty_fd, init_state(self.use_alternate_screen, self.mouse_tracking, focus_tracking=self.focus_tracking))
 
     def reset_state_to_original(self) -> None:
         normal_tty(self.tty_fd, self.original_termios)
@@ -238,6 +244,8 @@ def __init__(
         self.overlay_ready_reported = False
         self.optional_actions = optional_actions
         self.read_buf = ''
+        self.input_recording: Optional[IO[str]] = None
+        self.recording_started_at = 0.
         self.decoder = codecs.getincrementaldecoder('utf-8')('ignore')
         try:
             self.iov_limit = max(os.sysconf('SC_IOV_MAX') - 1, 255)
@@ -259,6 +267,13 @@ def _read_ready(self, handler: Handler, fd: int) -> None:
             handler.terminal_io_ended = True
             self.quit(1)
             return
+        if self.input_recording is not None:
+            self.input_recording.write(json.dumps([
+                monotonic() - self.recording_started_at, standard_b64encode(bdata).decode('ascii')]) + '\n')
+            self.input_recording.flush()
+        self.feed_input(handler, bdata)
+
+    def feed_input(self, handler: Handler, bdata: bytes) -> None:
         data = self.decoder.decode(bdata)
         if self.read_buf:
             data = self.read_buf + data
@@ -294,7 +309,6 @@ def _on_text(self, text: str) -> None:
 
     def _on_dcs(self, dcs: str) -> None:
         if dcs.startswith('@kitty-cmd'):
-            import json
             self.handler.on_kitty_cmd_response(json.loads(dcs[len('@kitty-cmd'):]))
         elif dcs.startswith('1+r'):
             from binascii import unhexlify
@@ -318,6 +332,8 @@ def _on_csi(self, csi: str) -> None:
                     pass
                 else:
                     self.handler.on_mouse_event(ev)
+        elif csi in ('I', 'O'):
+            self.handler.on_focus_change(csi == 'I')
         elif q in 'u~ABCDEHFPQRS':
             if csi == '200~':
                 self.in_bracketed_paste = True
@@ -381,6 +397,11 @@ def _write_ready(self, handler: Handler, fd: int) -> None:
                 handler.terminal_io_ended = True
                 self.quit(1)
                 return
+            self.last_write_progress_at = monotonic()
+            if self.write_stalled:
+                self.write_stalled = False
+                self._arm_write_stall_check(handler)
+                handler.on_write_stalled(False)
         else:
             written = 0
         if written >= total_size:
@@ -401,6 +422,37 @@ def _write_ready(self, handler: Handler, fd: int) -> None:
                 break
             del self.write_buf[:consumed]
 
+    def _arm_write_stall_check(self, handler: Handler) -> None:
+        if self.write_stall_check is None and handler.write_stall_timeout > 0:
+            self.write_stall_check = self.asyncio_loop.call_later(handler.write_stall_timeout, self._check_for_write_stall, handler)
+
+    def _check_for_write_stall(self, handler: Handler) -> None:
+        self.write_stall_check = None
+        if not self.waiting_for_writes or self.write_stalled or not self.total_pending_bytes_to_write:
+            return
+        remaining = handler.write_stall_timeout - (monotonic() - self.last_write_progress_at)
+        if remaining > 0:
+            self.write_stall_check = self.asyncio_loop.call_later(remaining, self._check_for_write_stall, handler)
+        else:
+            self.write_stalled = True
+            handler.on_write_stalled(True)
+
+    def record_input(self, path: str) -> None:
+        ' Record all input received from the terminal, with timestamps, to the specified file '
+        self.input_recording = open(path, 'w')
+        self.recording_started_at = monotonic()
+
+    def replay_input(self, handler: Handler, path: str, speed: float = 1) -> None:
+        '''
+        Feed input recorded with record_input() to the handler, at the recorded
+        times divided by speed. A speed of zero feeds it all immediately.
+        '''
+        with open(path) as f:
+            for line in f:
+                if line.strip():
+                    ts, data = json.loads(line)
+                    self.asyncio_loop.call_later(ts / speed if speed > 0 else 0, self.feed_input, handler, standard_b64decode(data))
+
     def quit(self, return_code: Optional[int] = None) -> None:
         if return_code is not None:
             self.return_code = return_code
@@ -411,12 +463,17 @@ def loop_impl(self, handler: Handler, term_manager: TermManager, image_manager:
         tty_fd = term_manager.tty_fd
         tb = None
         self.waiting_for_writes = True
+        self.write_stalled = False
+        self.last_write_progress_at = monotonic()
+        self.write_stall_check: Optional[asyncio.TimerHandle] = None
 
         def schedule_write(data: bytes) -> None:
             self.write_buf.append(data)
             if not self.waiting_for_writes:
                 self.asyncio_loop.add_writer(tty_fd, self._write_ready, handler, tty_fd)
                 self.waiting_for_writes = True
+                self.last_write_progress_at = monotonic()
+            self._arm_write_stall_check(handler)
 
         def handle_exception(loop: asyncio.AbstractEventLoop, context: Dict[str, Any]) -> None:
             nonlocal tb
@@ -440,6 +497,9 @@ def handle_exception(loop: asyncio.AbstractEventLoop, context: Dict[str, Any]) -
             self.asyncio_loop.remove_reader(tty_fd)
             if self.waiting_for_writes:
                 self.asyncio_loop.remove_writer(tty_fd)
+            if self.write_stall_check is not None:
+                self.write_stall_check.cancel()
+                self.write_stall_check = None
         return tb
 
     def loop(self, handler: Handler) -> None:
@@ -451,16 +511,26 @@ def _on_sigwinch() -> None:
             handler.on_resize(handler.screen_size)
 
         signal_manager = SignalManager(self.asyncio_loop, _on_sigwinch, handler.on_interrupt, handler.on_term, handler.on_hup)
-        with TermManager(self.optional_actions, handler.use_alternate_screen, handler.mouse_tracking) as term_manager, signal_manager:
+        with TermManager(
+            self.optional_actions, handler.use_alternate_screen, handler.mouse_tracking, handler.focus_tracking
+        ) as term_manager, signal_manager:
             self._get_screen_size: ScreenSizeGetter = screen_size_function(term_manager.tty_fd)
             image_manager = None
             if handler.image_manager_class is not None:
                 image_manager = handler.image_manager_class(handler)
             try:
+                if os.environ.get('KITTY_TUI_RECORD_INPUT'):
+                    self.record_input(os.environ['KITTY_TUI_RECORD_INPUT'])
+                if os.environ.get('KITTY_TUI_REPLAY_INPUT'):
+                    self.replay_input(handler, os.environ['KITTY_TUI_REPLAY_INPUT'], float(os.environ.get('KITTY_TUI_REPLAY_SPEED', '1')))
                 tb = self.loop_impl(handler, term_manager, image_manager)
             except Exception:
                 import traceback
                 tb = traceback.format_exc()
+            finally:
+                if self.input_recording is not None:
+                    self.input_recording.close()
+                    self.input_recording = None
 
             term_manager.extra_finalize = b''.join(self.write_buf).decode('utf-8')
             if tb is not None:
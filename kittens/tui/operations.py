@@ -8,7 +8,7 @@
 from typing import IO, Any, Callable, Dict, Generator, Optional, TypeVar, Union
 
 from kitty.fast_data_types import Color
-from kitty.rgb import color_as_sharp, to_color
+from kitty.rgb import alpha_blend, color_as_sharp, to_color
 from kitty.typing import (
     GraphicsCommandType, HandlerType, ScreenSize, UnderlineLiteral
 )
@@ -102,6 +102,26 @@ def set_window_title(value: str) -> str:
     return '\033]2;' + value.replace('\033', '').replace('\x9c', '') + '\033\\'
 
 
+@cmd
+def save_window_title() -> str:
+    return '\033[22;2t'  # push window title onto the title stack
+
+
+@cmd
+def restore_window_title() -> str:
+    return '\033[23;2t'  # pop window title from the title stack
+
+
+@contextmanager
+def window_title(write: Callable[[str], None], title: str) -> Generator[None, None, None]:
+    write(save_window_title())
+    write(set_window_title(title))
+    try:
+        yield
+    finally:
+        write(restore_window_title())
+
+
 @cmd
 def set_line_wrapping(yes_or_no: bool) -> str:
     return set_mode(Mode.DECAWM) if yes_or_no else reset_mode(Mode.DECAWM)
@@ -289,7 +309,10 @@ class MouseTracking(Enum):
     full = auto()
 
 
-def init_state(alternate_screen: bool = True, mouse_tracking: MouseTracking = MouseTracking.none, kitty_keyboard_mode: bool = True) -> str:
+def init_state(
+    alternate_screen: bool = True, mouse_tracking: MouseTracking = MouseTracking.none, kitty_keyboard_mode: bool = True,
+    focus_tracking: bool = False
+) -> str:
     sc = SAVE_CURSOR if alternate_screen else ''
     ans = (
         S7C1T + sc + SAVE_PRIVATE_MODE_VALUES + reset_mode(Mode.LNM) +
@@ -312,6 +335,8 @@ def init_state(alternate_screen: bool = True, mouse_tracking: MouseTracking = Mo
             ans += set_mode(Mode.MOUSE_MOTION_TRACKING)
         elif mouse_tracking is MouseTracking.full:
             ans += set_mode(Mode.MOUSE_MOVE_TRACKING)
+    if focus_tracking:
+        ans += set_mode(Mode.FOCUS_TRACKING)
     if kitty_keyboard_mode:
         ans += '\033[>31u'  # extended keyboard mode
     else:
@@ -404,6 +429,13 @@ def item(which: Optional[Union[Color, str]], num: int) -> None:
     return ans
 
 
+@cmd
+def set_cursor_and_selection_colors(bg: Color, cursor: Color, select_bg: Color, dimmed: bool = False) -> str:
+    if dimmed:
+        cursor, select_bg = alpha_blend(cursor, bg, 0.5), alpha_blend(select_bg, bg, 0.5)
+    return f'\x1b]12;{color_as_sharp(cursor)}\x1b\\\x1b]17;{color_as_sharp(select_bg)}\x1b\\'
+
+
 @cmd
 def save_colors() -> str:
     return '\x1b[#P'
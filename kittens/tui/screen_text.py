@@ -0,0 +1,160 @@
+#!/usr/bin/env python
+# License: GPLv3 Copyright: 2022, Kovid Goyal <kovid at kovidgoyal.net>
+
+import re
+from typing import Any, Dict, List, NamedTuple, Sequence, Tuple, Union
+
+from kitty.fast_data_types import wcswidth
+
+# None is the default color, an int is an index into the 256 color table
+ColorType = Union[None, int, Tuple[int, int, int]]
+escape_pat = re.compile(r'\x1b\[([0-9:;]*)m|\x1b\]([^\x1b]*)\x1b\\|\x1b\[[0-9:;<=>?]*[ -/]*[@-~]')
+
+
+class Style(NamedTuple):
+    fg: ColorType = None
+    bg: ColorType = None
+    decoration_fg: ColorType = None
+    bold: bool = False
+    dim: bool = False
+    italic: bool = False
+    reverse: bool = False
+    strike: bool = False
+    # 0 is no underline, 1 straight, 2 double and 3 curly
+    decoration: int = 0
+
+
+class Cell(NamedTuple):
+    # empty for the cell covered by the second half of a wide character
+    text: str
+    style: Style = Style()
+    hyperlink: str = ''
+
+
+def parse_color(args: Sequence[str], colon_separated: bool) -> Tuple[ColorType, int]:
+    ' Parse the arguments after 38, 48 or 58 returning the color and the number of arguments consumed '
+    try:
+        if args[0] == '5':
+            return int(args[1]), 2
+        if args[0] == '2':
+            vals = args[1:]
+            if colon_separated:
+                vals = vals[-3:]  # may have a leading color space id
+                n = len(args)
+            else:
+                vals = vals[:3]
+                n = 4
+            r, g, b = (int(x or '0') for x in vals)
+            return (r, g, b), n
+    except (IndexError, ValueError):
+        pass
+    return None, len(args) if colon_separated else 1
+
+
+def apply_sgr(params: str, style: Style) -> Style:
+    parts = params.split(';') if params else ['0']
+    s: Dict[str, Any] = style._asdict()
+    i = 0
+    while i < len(parts):
+        sub = parts[i].split(':')
+        i += 1
+        try:
+            code = int(sub[0] or '0')
+        except ValueError:
+            continue
+        if code == 0:
+            s = Style()._asdict()
+        elif code in (1, 2, 3, 7, 9):
+            s[{1: 'bold', 2: 'dim', 3: 'italic', 7: 'reverse', 9: 'strike'}[code]] = True
+        elif code == 4:
+            try:
+                s['decoration'] = max(0, min(int(sub[1] or '1'), 3)) if len(sub) > 1 else 1
+            except ValueError:
+                s['decoration'] = 1
+        elif code == 21:
+            s['decoration'] = 2
+        elif code == 22:
+            s['bold'] = s['dim'] = False
+        elif code in (23, 27, 29):
+            s[{23: 'italic', 27: 'reverse', 29: 'strike'}[code]] = False
+        elif code == 24:
+            s['decoration'] = 0
+        elif 30 <= code <= 37:
+            s['fg'] = code - 30
+        elif 40 <= code <= 47:
+            s['bg'] = code - 40
+        elif 90 <= code <= 97:
+            s['fg'] = code - 90 + 8
+        elif 100 <= code <= 107:
+            s['bg'] = code - 100 + 8
+        elif code in (39, 49, 59):
+            s[{39: 'fg', 49: 'bg', 59: 'decoration_fg'}[code]] = None
+        elif code in (38, 48, 58):
+            key = {38: 'fg', 48: 'bg', 58: 'decoration_fg'}[code]
+            if len(sub) > 1:
+                s[key] = parse_color(sub[1:], True)[0]
+            else:
+                s[key], consumed = parse_color(parts[i:], False)
+                i += consumed
+    return Style(**s)
+
+
+def parse_ansi_text(text: str, tab_size: int = 8) -> List[List[Cell]]:
+    '''
+    Convert text with SGR formatting and OSC 8 hyperlinks, as output by
+    :code:`kitty @ get-text --ansi`, into a list of rows of cells. Every row
+    has one cell per column, so that positions in the grid correspond to
+    positions on screen. Wrap markers (carriage returns) and other escape
+    codes are ignored.
+    '''
+    rows: List[List[Cell]] = []
+    row: List[Cell] = []
+    style = Style()
+    hyperlink = ''
+
+    def add_text(chunk: str) -> None:
+        nonlocal row
+        for ch in chunk:
+            if ch == '\n':
+                rows.append(row)
+                row = []
+            elif ch == '\t':
+                for x in range(tab_size - len(row) % tab_size):
+                    row.append(Cell(' ', style, hyperlink))
+            elif ch >= ' ':
+                w = wcswidth(ch)
+                if w < 1:
+                    if row:  # combining character
+                        idx = len(row) - 1
+                        if not row[idx].text and idx > 0:
+                            idx -= 1
+                        row[idx] = row[idx]._replace(text=row[idx].text + ch)
+                    continue
+                row.append(Cell(ch, style, hyperlink))
+                if w > 1:
+                    row.append(Cell('', style, hyperlink))
+
+    pos = 0
+    for m in escape_pat.finditer(text):
+        add_text(text[pos:m.start()])
+        pos = m.end()
+        sgr, osc = m.group(1), m.group(2)
+        if sgr is not None:
+            style = apply_sgr(sgr, style)
+        elif osc is not None and osc.startswith('8;'):
+            hyperlink = osc[2:].partition(';')[2]
+    add_text(text[pos:])
+    rows.append(row)
+    return rows
+
+
+def row_as_text(row: Sequence[Cell]) -> Tuple[str, List[int]]:
+    ' Return the text of a row along with the column number of every character in the text '
+    chars: List[str] = []
+    columns: List[int] = []
+    for x, cell in enumerate(row):
+        for ch in cell.text:
+            chars.append(ch)
+            columns.append(x)
+    return ''.join(chars), columns
+
@@ -13,7 +13,8 @@ class CLIOptions:
 HintsCLIOptions = IcatCLIOptions = PanelCLIOptions = ResizeCLIOptions = CLIOptions
 ErrorCLIOptions = UnicodeCLIOptions = RCOptions = RemoteFileCLIOptions = CLIOptions
 QueryTerminalCLIOptions = BroadcastCLIOptions = ShowKeyCLIOptions = CLIOptions
-ThemesCLIOptions = TransferCLIOptions = CopyCLIOptions = CLIOptions
+ThemesCLIOptions = TransferCLIOptions = CopyCLIOptions = NotifyCLIOptions = BenchmarkCLIOptions = ResetTerminalCLIOptions = CLIOptions
+SearchCLIOptions = LayoutDesignerCLIOptions = CLIOptions
 
 
 def generate_stub() -> None:
@@ -81,6 +82,21 @@ def do(otext=None, cls: str = 'CLIOptions', extra_fields: Sequence[str] = ()):
     from kittens.ssh.copy import option_text as OPTIONS
     do(OPTIONS(), 'CopyCLIOptions')
 
+    from kittens.notify.main import OPTIONS
+    do(OPTIONS(), 'NotifyCLIOptions')
+
+    from kittens.benchmark.main import OPTIONS
+    do(OPTIONS(), 'BenchmarkCLIOptions')
+
+    from kittens.reset_terminal.main import OPTIONS
+    do(OPTIONS(), 'ResetTerminalCLIOptions')
+
+    from kittens.search.main import OPTIONS
+    do(OPTIONS(), 'SearchCLIOptions')
+
+    from kittens.layout_designer.main import OPTIONS
+    do(OPTIONS(), 'LayoutDesignerCLIOptions')
+
     from kitty.rc.base import all_command_names, command_for_name
     for cmd_name in all_command_names():
         cmd = command_for_name(cmd_name)
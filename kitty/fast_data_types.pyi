@@ -842,7 +842,7 @@ def concat_cells(cell_width: int, cell_height: int, is_32_bit: bool, cells: Tupl
     pass
 
 
-def current_fonts() -> Dict[str, Any]:
+def current_fonts(os_window_id: int = 0) -> Dict[str, Any]:
     pass
 
 
@@ -1596,11 +1596,21 @@ concat_cells(PyObject UNUSED *self, PyObject *args) {
 }
 
 static PyObject*
-current_fonts(PYNOARG) {
+current_fonts(PyObject *self UNUSED, PyObject *args) {
+    id_type os_window_id = 0;
+    if (!PyArg_ParseTuple(args, "|K", &os_window_id)) return NULL;
     if (!num_font_groups) { PyErr_SetString(PyExc_RuntimeError, "must create font group first"); return NULL; }
+    FontGroup *fg = font_groups;
+    if (os_window_id) {
+        fg = NULL;
+        for (size_t o = 0; o < global_state.num_os_windows; o++) {
+            OSWindow *w = global_state.os_windows + o;
+            if (w->id == os_window_id) { fg = (FontGroup*)w->fonts_data; break; }
+        }
+        if (!fg) { PyErr_Format(PyExc_KeyError, "No OS window with id: %llu", os_window_id); return NULL; }
+    }
     PyObject *ans = PyDict_New();
     if (!ans) return NULL;
-    FontGroup *fg = font_groups;
 #define SET(key, val) {if (PyDict_SetItemString(ans, #key, fg->fonts[val].face) != 0) { goto error; }}
     SET(medium, fg->medium_font_idx);
     if (fg->bold_font_idx > 0) SET(bold, fg->bold_font_idx);
@@ -1682,7 +1692,7 @@ static PyMethodDef module_methods[] = {
     METHODB(concat_cells, METH_VARARGS),
     METHODB(set_send_sprite_to_gpu, METH_O),
     METHODB(test_shape, METH_VARARGS),
-    METHODB(current_fonts, METH_NOARGS),
+    METHODB(current_fonts, METH_VARARGS),
     METHODB(test_render_line, METH_VARARGS),
     METHODB(get_fallback_font, METH_VARARGS),
     {NULL, NULL, 0, NULL}        /* Sentinel */
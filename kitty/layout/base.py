@@ -4,8 +4,8 @@
 from functools import partial
 from itertools import repeat
 from typing import (
-    Any, Dict, Generator, Iterable, Iterator, List, NamedTuple, Optional,
-    Sequence, Tuple, Union
+    Any, Callable, Dict, Generator, Iterable, Iterator, List, NamedTuple,
+    Optional, Sequence, Tuple, Union
 )
 
 from kitty.borders import BorderColor
@@ -406,3 +406,6 @@ def layout_action(self, action_name: str, args: Sequence[str], all_windows: Wind
 
     def layout_state(self) -> Dict[str, Any]:
         return {}
+
+    def set_layout_state(self, all_windows: WindowList, layout_state: Dict[str, Any], map_group_id: Callable[[int], Optional[int]]) -> bool:
+        return False
@@ -2,8 +2,8 @@
 # License: GPLv3 Copyright: 2020, Kovid Goyal <kovid at kovidgoyal.net>
 
 from typing import (
-    Any, Collection, Dict, Generator, List, NamedTuple, Optional, Sequence, Tuple,
-    Union
+    Any, Callable, Collection, Dict, Generator, List, NamedTuple, Optional,
+    Sequence, Tuple, Union
 )
 
 from kitty.borders import BorderColor
@@ -566,8 +566,35 @@ def add_pair(p: Pair) -> Dict[str, Any]:
                 ans['one'] = p.one
             if isinstance(p.two, Pair):
                 ans['two'] = add_pair(p.two)
-            elif p.one is not None:
+            elif p.two is not None:
                 ans['two'] = p.two
             return ans
 
         return {'pairs': add_pair(self.pairs_root)}
+
+    def set_layout_state(self, all_windows: WindowList, layout_state: Dict[str, Any], map_group_id: Callable[[int], Optional[int]]) -> bool:
+        # the inverse of layout_state() with the leaves mapped to group ids by map_group_id
+        mapped_ids: List[Optional[int]] = []
+
+        def create_pair(state: Dict[str, Any]) -> Pair:
+            ans = Pair(horizontal=bool(state.get('horizontal', True)))
+            ans.bias = max(0.1, min(float(state.get('bias', 0.5)), 0.9))
+            for attr in ('one', 'two'):
+                q = state.get(attr)
+                if isinstance(q, dict):
+                    setattr(ans, attr, create_pair(q))
+                elif q is not None:
+                    gid = map_group_id(int(q))
+                    mapped_ids.append(gid)
+                    setattr(ans, attr, gid)
+            return ans
+
+        try:
+            root = create_pair(layout_state['pairs'])
+        except Exception:
+            return False
+        group_ids = [g.id for g in all_windows.iter_all_layoutable_groups()]
+        if None in mapped_ids or sorted(mapped_ids) != sorted(group_ids):  # type: ignore
+            return False
+        self.pairs_root = root
+        return True
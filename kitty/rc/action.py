@@ -0,0 +1,78 @@
+#!/usr/bin/env python
+# License: GPLv3 Copyright: 2022, Kovid Goyal <kovid at kovidgoyal.net>
+
+from typing import TYPE_CHECKING, Iterable, Optional
+
+from .base import (
+    MATCH_WINDOW_OPTION, ArgsType, Boss, PayloadGetType, PayloadType,
+    RCOptions, RemoteCommand, ResponseType, Window
+)
+
+if TYPE_CHECKING:
+    from kitty.cli_stub import ActionRCOptions as CLIOptions
+
+
+class UnknownAction(ValueError):
+
+    hide_traceback = True
+
+
+def action_names() -> Iterable[str]:
+    from kitty.actions import get_all_actions
+    return sorted(a.name for group in get_all_actions().values() for a in group)
+
+
+class Action(RemoteCommand):
+
+    '''
+    action+/str: The action to perform, with its arguments, in the same syntax as used for map in kitty.conf
+    match/str: The window to perform the action in
+    '''
+
+    short_desc = 'Perform the specified action'
+    desc = (
+        'Perform the specified action in the specified window (defaults to the active window).'
+        ' Any action that can be mapped to a keyboard shortcut in :file:`kitty.conf` is allowed,'
+        ' including :code:`combine` and any aliases defined with :opt:`action_alias`. For example::\n\n'
+        '    kitty @ action toggle_layout stack\n\n'
+        '    kitty @ action --match title:vim move_window_to_top'
+    )
+    options_spec = MATCH_WINDOW_OPTION
+    argspec = 'ACTION [ARGS FOR ACTION...]'
+    args_completion = {'names': ('Actions', action_names)}
+
+    def message_to_kitty(self, global_opts: RCOptions, opts: 'CLIOptions', args: ArgsType) -> PayloadType:
+        if not args:
+            self.fatal('Must specify an action')
+        action = ' '.join(args)
+        from kitty.options.utils import parse_key_action
+        try:
+            parse_key_action(action)
+        except KeyError:
+            pass  # could be an alias defined in kitty.conf, let kitty check it
+        except Exception as err:
+            self.fatal(f'Invalid arguments for action: {args[0]} with error: {err}')
+        return {'action': action, 'match': opts.match}
+
+    def response_from_kitty(self, boss: Boss, window: Optional[Window], payload_get: PayloadGetType) -> ResponseType:
+        from kitty.fast_data_types import get_options
+        windows = self.windows_for_match_payload(boss, window, payload_get)
+        if not windows or not windows[0]:
+            return None
+        defn = payload_get('action')
+        try:
+            actions = get_options().alias_map.resolve_aliases(defn)
+        except Exception as err:
+            raise UnknownAction(f'Failed to parse the action: {defn} with error: {err}')
+        known = frozenset(action_names())
+        for ka in actions:
+            if ka.func not in known:
+                raise UnknownAction(f'Unknown action: {ka.func}')
+        if actions:
+            boss.dispatch_action(actions[0], windows[0])
+            if len(actions) > 1:
+                boss.drain_actions(list(actions[1:]), windows[0])
+        return None
+
+
+action = Action()
@@ -1,6 +1,8 @@
 #!/usr/bin/env python
 # License: GPLv3 Copyright: 2020, Kovid Goyal <kovid at kovidgoyal.net>
 
+import json
+import sys
 from contextlib import suppress
 from typing import (
     TYPE_CHECKING, Any, Callable, Dict, FrozenSet, Iterable, Iterator, List,
@@ -256,6 +258,18 @@ def response_from_kitty(self, boss: 'Boss', window: Optional['Window'], payload_
     def cancel_async_request(self, boss: 'Boss', window: Optional['Window'], payload_get: PayloadGetType) -> None:
         pass
 
+    def reads_stdin(self, opts: Any, args: ArgsType) -> bool:
+        return False
+
+
+def is_stdin_path(path: str) -> bool:
+    return path in ('-', '/dev/stdin', '/dev/fd/0', '/proc/self/fd/0')
+
+
+def report_progress(global_opts: RCOptions, sent: int, total: int) -> None:
+    if global_opts.progress:
+        print(json.dumps({'sent': sent, 'total': total}), file=sys.stderr, flush=True)
+
 
 def cli_params_for(command: RemoteCommand) -> Tuple[Callable[[], str], str, str, str]:
     return (command.options_spec or '\n').format, command.argspec, command.desc, f'{appname} @ {command.name}'
@@ -0,0 +1,106 @@
+#!/usr/bin/env python
+# License: GPLv3 Copyright: 2022, Kovid Goyal <kovid at kovidgoyal.net>
+
+import json
+import shlex
+from typing import TYPE_CHECKING, Any, Dict, List, Optional
+
+from .base import (
+    ArgsType, Boss, PayloadGetType, PayloadType, RCOptions, RemoteCommand,
+    ResponseType, Window
+)
+
+if TYPE_CHECKING:
+    from kitty.cli_stub import ExportSessionRCOptions as CLIOptions
+
+
+def launch_line(w: Window, shell: List[str]) -> str:
+    from kitty.options.utils import DELETE_ENV_VAR
+    child = w.child
+    cmd = list(getattr(child, 'unmodified_argv', None) or child.argv)
+    args = ['launch']
+    cwd = child.current_cwd or child.cwd
+    if cwd:
+        args.append(f'--cwd={cwd}')
+    if w.override_title:
+        args.append(f'--title={w.override_title}')
+    for k, v in child.env.items():
+        if v != DELETE_ENV_VAR:
+            args.append(f'--env={k}={v}')
+    if cmd and cmd != shell:
+        args.extend(cmd)
+    return shlex.join(args)
+
+
+def remap_pairs(state: Dict[str, Any], idx_map: Dict[int, int]) -> Dict[str, Any]:
+    ans = {'horizontal': state['horizontal'], 'bias': state['bias']}
+    for attr in ('one', 'two'):
+        q = state.get(attr)
+        if isinstance(q, dict):
+            ans[attr] = remap_pairs(q, idx_map)
+        elif q is not None:
+            ans[attr] = idx_map[q]
+    return ans
+
+
+class ExportSession(RemoteCommand):
+
+    '''
+    match_tab/str: Only export the tabs matching this expression
+    '''
+
+    short_desc = 'Export the current layout as a session file'
+    desc = (
+        'Print a :ref:`session file <sessions>` that re-creates the current arrangement of'
+        ' OS windows, tabs and windows. For every window the working directory, title, extra'
+        ' environment variables and command line used to launch it are preserved. Windows running'
+        ' the default shell are launched without a command, so they use whatever shell is configured.'
+        ' For tabs using the :ref:`splits layout <splits_layout>` the tree of splits, including the'
+        ' orientation and relative size of every split, is preserved as well. Overlay windows are not exported.'
+        ' Save the output to a file and use it with :option:`kitty --session`.'
+    )
+    options_spec = '''\
+--match-tab -m
+Only export the tabs matching the specified expression. See :ref:`search_syntax`
+for the syntax.
+'''
+    argspec = ''
+
+    def message_to_kitty(self, global_opts: RCOptions, opts: 'CLIOptions', args: ArgsType) -> PayloadType:
+        return {'match_tab': opts.match_tab}
+
+    def response_from_kitty(self, boss: Boss, window: Optional[Window], payload_get: PayloadGetType) -> ResponseType:
+        from kitty.fast_data_types import get_options
+        from kitty.utils import resolved_shell
+        shell = resolved_shell(get_options())
+        match = payload_get('match_tab')
+        allowed = set(boss.match_tabs(match)) if match else None
+        lines: List[str] = []
+        for tm in boss.os_window_map.values():
+            tabs = [t for t in tm if allowed is None or t in allowed]
+            if not tabs:
+                continue
+            if lines:
+                lines.extend(('', 'new_os_window'))
+            if tm.wm_class:
+                lines.append(f'os_window_class {tm.wm_class}')
+            for i, tab in enumerate(tabs):
+                if i:
+                    lines.append('')
+                lines.append(f'new_tab {tab.name}'.rstrip())
+                lines.append(f'layout {tab.current_layout.name}')
+                lines.append(f'enabled_layouts {",".join(tab.enabled_layouts)}')
+                idx_map: Dict[int, int] = {}
+                active_group = tab.windows.active_group
+                for g in tab.windows.iter_all_layoutable_groups():
+                    idx_map[g.id] = len(idx_map)
+                    lines.append(launch_line(g.windows[0], shell))
+                    if g is active_group and tab is tm.active_tab:
+                        lines.append('focus')
+                if tab.current_layout.name == 'splits' and len(idx_map) > 1:
+                    state = remap_pairs(tab.current_layout.layout_state()['pairs'], idx_map)
+                    lines.append(f'layout_state {json.dumps(state, separators=(",", ":"))}')
+        return '\n'.join(lines)
+
+
+export_session = ExportSession()
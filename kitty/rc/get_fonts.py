@@ -0,0 +1,49 @@
+#!/usr/bin/env python
+# License: GPLv3 Copyright: 2022, Kovid Goyal <kovid at kovidgoyal.net>
+
+import json
+from typing import TYPE_CHECKING, Any, Dict, Optional
+
+from .base import (
+    MATCH_WINDOW_OPTION, ArgsType, Boss, PayloadGetType, PayloadType,
+    RCOptions, RemoteCommand, ResponseType, Window
+)
+
+if TYPE_CHECKING:
+    from kitty.cli_stub import GetFontsRCOptions as CLIOptions
+
+
+class GetFonts(RemoteCommand):
+
+    '''
+    match/str: The window whose OS window to get the font size for
+    '''
+
+    short_desc = 'Get the font size and fonts in use'
+    desc = (
+        'Get the font size, in pts, of the OS window containing the specified window'
+        ' (defaults to active window), along with the names of the fonts used for'
+        ' the regular, bold, italic and bold-italic faces and any fallback fonts'
+        ' that have been loaded so far. The result is output as JSON.'
+    )
+    options_spec = MATCH_WINDOW_OPTION
+
+    def message_to_kitty(self, global_opts: RCOptions, opts: 'CLIOptions', args: ArgsType) -> PayloadType:
+        return {'match': opts.match}
+
+    def response_from_kitty(self, boss: Boss, window: Optional[Window], payload_get: PayloadGetType) -> ResponseType:
+        from kitty.fast_data_types import current_fonts, os_window_font_size
+        windows = self.windows_for_match_payload(boss, window, payload_get)
+        if not windows or not windows[0]:
+            return None
+        os_window_id = windows[0].os_window_id
+        ans: Dict[str, Any] = {'font_size': os_window_font_size(os_window_id)}
+        cf = current_fonts(os_window_id)
+        for key, name in (('medium', 'regular'), ('bold', 'bold'), ('italic', 'italic'), ('bi', 'bold_italic')):
+            face = cf.get(key)
+            ans[name] = face.display_name() if face is not None else None
+        ans['fallback'] = [f.display_name() for f in cf['fallback']]
+        return json.dumps(ans, indent=2, sort_keys=True)
+
+
+get_fonts = GetFonts()
@@ -27,7 +27,10 @@ class LS(RemoteCommand):
         ' of :italic:`tabs`. Each tab has its own :italic:`id`, a :italic:`title` and a list of :italic:`windows`.'
         ' Each window has an :italic:`id`, :italic:`title`, :italic:`current working directory`, :italic:`process id (PID)`, '
         ' :italic:`command-line` and :italic:`environment` of the process running in the window. Additionally, when'
-        ' running the command inside a kitty window, that window can be identified by the :italic:`is_self` parameter.\n\n'
+        ' running the command inside a kitty window, that window can be identified by the :italic:`is_self` parameter.'
+        ' Each tab also has a list of window :italic:`groups`, each with an :italic:`id` and the ids of the windows in it.'
+        ' A group is a window along with any overlay windows on top of it. Layouts arrange groups, so the ids'
+        ' in :italic:`layout_state` refer to groups.\n\n'
         'You can use these criteria to select windows/tabs for the other commands.'
     )
     options_spec = '''\
@@ -2,6 +2,7 @@
 # License: GPLv3 Copyright: 2020, Kovid Goyal <kovid at kovidgoyal.net>
 
 import base64
+import os
 import sys
 from typing import TYPE_CHECKING, Any, Dict, List, Optional, Set, Union
 
@@ -12,7 +13,7 @@
 from .base import (
     MATCH_TAB_OPTION, MATCH_WINDOW_OPTION, ArgsType, Boss, CmdGenerator,
     MatchError, PayloadGetType, PayloadType, RCOptions, RemoteCommand,
-    ResponseType, Window
+    ResponseType, Window, is_stdin_path, report_progress
 )
 
 if TYPE_CHECKING:
@@ -103,6 +104,9 @@ class SendText(RemoteCommand):
     no_response = True
     argspec = '[TEXT TO SEND]'
 
+    def reads_stdin(self, opts: 'CLIOptions', args: ArgsType) -> bool:
+        return opts.stdin or is_stdin_path(opts.from_file or '')
+
     def message_to_kitty(self, global_opts: RCOptions, opts: 'CLIOptions', args: ArgsType) -> PayloadType:
         limit = 1024
         ret = {'match': opts.match, 'data': '', 'match_tab': opts.match_tab, 'all': opts.all, 'exclude_active': opts.exclude_active}
@@ -141,6 +145,7 @@ def chunks(text: str) -> CmdGenerator:
                 data = data[limit:]
 
         def file_pipe(path: str) -> CmdGenerator:
+            sent, total = 0, os.path.getsize(path)
             with open(path, 'rb') as f:
                 while True:
                     data = f.read(limit)
@@ -148,6 +153,8 @@ def file_pipe(path: str) -> CmdGenerator:
                         break
                     ret['data'] = f'base64:{base64.standard_b64encode(data).decode("ascii")}'
                     yield ret
+                    sent += len(data)
+                    report_progress(global_opts, sent, total)
 
         sources = []
         if opts.stdin:
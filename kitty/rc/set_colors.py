@@ -11,7 +11,8 @@
 
 from .base import (
     MATCH_TAB_OPTION, MATCH_WINDOW_OPTION, ArgsType, Boss, ParsingOfArgsFailed,
-    PayloadGetType, PayloadType, RCOptions, RemoteCommand, ResponseType, Window
+    PayloadGetType, PayloadType, RCOptions, RemoteCommand, ResponseType, Window,
+    is_stdin_path
 )
 
 if TYPE_CHECKING:
@@ -92,6 +93,9 @@ class SetColors(RemoteCommand):
     argspec = 'COLOR_OR_FILE ...'
     args_completion = {'files': ('CONF files', ('*.conf',))}
 
+    def reads_stdin(self, opts: 'CLIOptions', args: ArgsType) -> bool:
+        return any('=' not in spec and is_stdin_path(spec) for spec in args)
+
     def message_to_kitty(self, global_opts: RCOptions, opts: 'CLIOptions', args: ArgsType) -> PayloadType:
         final_colors: Dict[str, Optional[int]] = {}
         if not opts.reset:
@@ -12,7 +12,8 @@
 
 from .base import (
     MATCH_WINDOW_OPTION, ArgsType, Boss, CmdGenerator, PayloadGetType,
-    PayloadType, RCOptions, RemoteCommand, ResponseType, Window
+    PayloadType, RCOptions, RemoteCommand, ResponseType, Window,
+    is_stdin_path, report_progress
 )
 
 if TYPE_CHECKING:
@@ -65,6 +66,9 @@ class SetWindowLogo(RemoteCommand):
     images_in_flight: Dict[str, IO[bytes]] = {}
     is_asynchronous = True
 
+    def reads_stdin(self, opts: 'CLIOptions', args: ArgsType) -> bool:
+        return any(is_stdin_path(x) for x in args)
+
     def message_to_kitty(self, global_opts: RCOptions, opts: 'CLIOptions', args: ArgsType) -> PayloadType:
         if len(args) != 1:
             self.fatal('Must specify path to exactly one PNG image')
@@ -82,6 +86,7 @@ def message_to_kitty(self, global_opts: RCOptions, opts: 'CLIOptions', args: Arg
             self.fatal(f'{path} is not a PNG image')
 
         def file_pipe(path: str) -> CmdGenerator:
+            sent, total = 0, os.path.getsize(path)
             with open(path, 'rb') as f:
                 while True:
                     data = f.read(512)
@@ -89,6 +94,8 @@ def file_pipe(path: str) -> CmdGenerator:
                         break
                     ret['data'] = standard_b64encode(data).decode('ascii')
                     yield ret
+                    sent += len(data)
+                    report_progress(global_opts, sent, total)
             ret['data'] = ''
             yield ret
         return file_pipe(path)
@@ -23,8 +23,8 @@
     get_options, read_command_response, send_data_to_peer
 )
 from .rc.base import (
-    NoResponse, ParsingOfArgsFailed, PayloadGetter, all_command_names,
-    command_for_name, parse_subcommand_cli
+    NoResponse, ParsingOfArgsFailed, PayloadGetter, RemoteCommand,
+    all_command_names, command_for_name, parse_subcommand_cli
 )
 from .types import AsyncResponse
 from .typing import BossType, WindowType
@@ -232,6 +232,50 @@ def handle_cmd(boss: BossType, window: Optional[WindowType], cmd: Dict[str, Any]
 If no password is available, kitty will usually just send the remote control command
 without a password. This option can be used to force it to :code:`always` or :code:`never` use
 the supplied password.
+
+
+--timeout
+type=float
+default=0
+The maximum number of seconds to wait for a response from kitty. The default
+of zero means use the timeout appropriate for the command being run. Options
+to individual commands that control the response timeout take precedence.
+
+
+--wait-for-approval
+type=float
+default=120
+The number of seconds to wait for a response from kitty when a password is
+used, to give the user time to respond if kitty asks them for permission to run
+the command.
+
+
+--retry
+type=int
+default=0
+The number of times to retry connecting to the kitty instance specified by
+:option:`kitty @ --to`, if it is not accepting connections, waiting a little
+longer between each attempt. Commands are never re-sent once they have been
+received by kitty, so this is safe to use with any command.
+
+
+--progress
+type=bool-set
+Report progress for commands that send files to kitty, such as
+:ref:`at-set-background-image`, as lines of JSON on STDERR, each of the form
+:code:`{{"sent": bytes sent so far, "total": total bytes}}`.
+
+
+--stdin-commands
+type=bool-set
+Read commands from STDIN, one per line, and send them all to kitty over a single
+connection, instead of running a single command. Each line is a command with its
+arguments, as it would be written after :code:`{appname} @`. Blank lines and
+lines starting with :code:`#` are ignored. This is much faster than running
+:code:`{appname} @` once per command when scripting many commands. When STDIN is
+itself the terminal, the terminal is only used for the connection while each
+command runs, so that you can type the next command. Commands that would read
+from STDIN, such as :code:`send-text --stdin`, are not allowed.
 '''.format, appname=appname)
 
 
@@ -246,14 +290,25 @@ class SocketClosed(EOFError):
 
 class SocketIO:
 
-    def __init__(self, to: str):
+    def __init__(self, to: str, retries: int = 0):
         self.family, self.address = parse_address_spec(to)[:2]
+        self.retries = max(0, retries)
 
     def __enter__(self) -> None:
         import socket
-        self.socket = socket.socket(self.family)
-        self.socket.setblocking(True)
-        self.socket.connect(self.address)
+        from time import sleep
+        for attempt in range(self.retries + 1):
+            self.socket = socket.socket(self.family)
+            self.socket.setblocking(True)
+            try:
+                self.socket.connect(self.address)
+            except (FileNotFoundError, ConnectionRefusedError):
+                self.socket.close()
+                if attempt >= self.retries:
+                    raise
+                sleep(min(0.1 * 2 ** attempt, 2))
+            else:
+                break
 
     def __exit__(self, *a: Any) -> None:
         import socket
@@ -288,6 +343,61 @@ def simple_recv(self, timeout: float) -> bytes:
         return bytes(m.group(1))
 
 
+class PersistentSocketIO(SocketIO):
+
+    def __init__(self, to: str, retries: int = 0):
+        super().__init__(to, retries)
+        self.connected = False
+        self.pending = b''
+
+    def __enter__(self) -> None:
+        if not self.connected:
+            super().__enter__()
+            self.connected = True
+            self.pending = b''
+
+    def __exit__(self, *a: Any) -> None:
+        pass
+
+    def close(self) -> None:
+        if self.connected:
+            self.connected = False
+            super().__exit__()
+
+    def send(self, data: Union[bytes, Iterable[Union[str, bytes]]]) -> None:
+        if isinstance(data, bytes):
+            self.socket.sendall(data)
+        else:
+            for chunk in data:
+                if isinstance(chunk, str):
+                    chunk = chunk.encode('utf-8')
+                self.socket.sendall(chunk)
+
+    def simple_recv(self, timeout: float) -> bytes:
+        import socket
+        dcs = re.compile(br'\x1bP@kitty-cmd([^\x1b]+)\x1b\\')
+        st = monotonic()
+        while True:
+            m = dcs.search(self.pending)
+            if m is not None:
+                self.pending = self.pending[m.end():]
+                return bytes(m.group(1))
+            remaining = timeout - (monotonic() - st)
+            try:
+                if remaining <= 0:
+                    raise socket.timeout()
+                self.socket.settimeout(remaining)
+                data = self.socket.recv(8192)
+            except socket.timeout:
+                # a late response must not be mistaken for the response to the next command
+                self.close()
+                raise TimeoutError('Timed out while waiting to read cmd response')
+            if not data:
+                self.close()
+                raise SocketClosed('Remote control connection was closed by kitty without any response being received')
+            self.pending += data
+
+
 class RCIO(TTYIO):
 
     def simple_recv(self, timeout: float) -> bytes:
@@ -296,8 +406,38 @@ def simple_recv(self, timeout: float) -> bytes:
         return b''.join(ans)
 
 
+class PersistentRCIO(RCIO):
+
+    def __init__(self) -> None:
+        super().__init__()
+        self.opened = False
+
+    def __enter__(self) -> 'PersistentRCIO':
+        if not self.opened:
+            super().__enter__()
+            self.opened = True
+        return self
+
+    def __exit__(self, *a: Any) -> None:
+        pass
+
+    def close(self) -> None:
+        if self.opened:
+            self.opened = False
+            super().__exit__()
+
+    def simple_recv(self, timeout: float) -> bytes:
+        try:
+            return super().simple_recv(timeout)
+        except TimeoutError:
+            # a late response must not be mistaken for the response to the next command
+            self.close()
+            raise
+
+
 def do_io(
-    to: Optional[str], original_cmd: Dict[str, Any], no_response: bool, response_timeout: float, encrypter: 'CommandEncrypter'
+    to: Optional[str], original_cmd: Dict[str, Any], no_response: bool, response_timeout: float, encrypter: 'CommandEncrypter',
+    retries: int = 0, io: Optional[Union[SocketIO, RCIO]] = None
 ) -> Dict[str, Any]:
     payload = original_cmd.get('payload')
     if not isinstance(payload, GeneratorType):
@@ -310,7 +450,8 @@ def send_generator() -> Iterator[bytes]:
                 yield encode_send(encrypter(original_cmd))
         send_data = send_generator()
 
-    io: Union[SocketIO, RCIO] = SocketIO(to) if to else RCIO()
+    if io is None:
+        io = SocketIO(to, retries) if to else RCIO()
     with io:
         io.send(send_data)
         if no_response:
@@ -366,8 +507,12 @@ def __call__(self, cmd: Dict[str, Any]) -> Dict[str, Any]:
             ans['enc_proto'] = self.encryption_version
         return ans
 
-    def adjust_response_timeout_for_password(self, response_timeout: float) -> float:
-        return max(response_timeout, 120)
+    def adjust_response_timeout_for_password(self, response_timeout: float, wait_for_approval: float = 120) -> float:
+        return max(response_timeout, wait_for_approval)
+
+    def timed_out_message(self, response_timeout: float) -> str:
+        return (f'Timed out after {response_timeout} seconds waiting for response from kitty. If kitty asked for permission to run'
+                ' this command, use --wait-for-approval to give yourself more time to approve it')
 
 
 class NoEncryption(CommandEncrypter):
@@ -379,9 +524,21 @@ def __init__(self) -> None: ...
     def __call__(self, cmd: Dict[str, Any]) -> Dict[str, Any]:
         return cmd
 
-    def adjust_response_timeout_for_password(self, response_timeout: float) -> float:
+    def adjust_response_timeout_for_password(self, response_timeout: float, wait_for_approval: float = 120) -> float:
         return response_timeout
 
+    def timed_out_message(self, response_timeout: float) -> str:
+        return f'Timed out after {response_timeout} seconds waiting for response from kitty, use --timeout to wait longer'
+
+
+def response_timeout_for(global_opts: RCOptions, c: RemoteCommand, opts: Any, encrypter: CommandEncrypter) -> float:
+    response_timeout = c.response_timeout
+    if hasattr(opts, 'response_timeout'):
+        response_timeout = opts.response_timeout
+    elif global_opts.timeout > 0:
+        response_timeout = global_opts.timeout
+    return encrypter.adjust_response_timeout_for_password(response_timeout, global_opts.wait_for_approval)
+
 
 def create_basic_command(name: str, payload: Any = None, no_response: bool = False, is_asynchronous: bool = False) -> Dict[str, Any]:
     ans = {'cmd': name, 'version': version, 'no_response': no_response}
@@ -455,19 +612,10 @@ def get_pubkey() -> Tuple[str, bytes]:
     return version, b85decode(pubkey)
 
 
-def main(args: List[str]) -> None:
-    global_opts, items = parse_rc_args(args)
-    password = get_password(global_opts)
-    if password:
-        encryption_version, pubkey = get_pubkey()
-        encrypter = CommandEncrypter(pubkey, encryption_version, password)
-    else:
-        encrypter = NoEncryption()
-
-    if not items:
-        from kitty.shell import main as smain
-        smain(global_opts, encrypter)
-        return
+def run_command(
+    global_opts: RCOptions, encrypter: 'CommandEncrypter', items: List[str],
+    io: Optional[Union[PersistentSocketIO, PersistentRCIO]] = None
+) -> None:
     cmd = items[0]
     try:
         c = command_for_name(cmd)
@@ -475,6 +623,8 @@ def main(args: List[str]) -> None:
         raise SystemExit('{} is not a known command. Known commands are: {}'.format(
             emph(cmd), ', '.join(x.replace('_', '-') for x in all_command_names())))
     opts, items = parse_subcommand_cli(c, items)
+    if global_opts.stdin_commands and c.reads_stdin(opts, items):
+        raise SystemExit(f'The {emph(cmd)} command cannot read from STDIN when commands are themselves being read from STDIN')
     try:
         payload = c.message_to_kitty(global_opts, opts, items)
     except ParsingOfArgsFailed as err:
@@ -482,42 +632,29 @@ def main(args: List[str]) -> None:
     no_response = c.no_response
     if hasattr(opts, 'no_response'):
         no_response = opts.no_response
-    response_timeout = c.response_timeout
-    if hasattr(opts, 'response_timeout'):
-        response_timeout = opts.response_timeout
-    response_timeout = encrypter.adjust_response_timeout_for_password(response_timeout)
+    response_timeout = response_timeout_for(global_opts, c, opts, encrypter)
     send = create_basic_command(cmd, payload=payload, no_response=no_response, is_asynchronous=c.is_asynchronous)
-    listen_on_from_env = False
-    if not global_opts.to and 'KITTY_LISTEN_ON' in os.environ:
-        global_opts.to = os.environ['KITTY_LISTEN_ON']
-        listen_on_from_env = False
-    if global_opts.to:
-        try:
-            parse_address_spec(global_opts.to)
-        except Exception:
-            msg = f'Invalid listen on address: {global_opts.to}'
-            if listen_on_from_env:
-                msg += '. The KITTY_LISTEN_ON environment variable is set incorrectly'
-            exit(msg)
     import socket
     try:
-        response = do_io(global_opts.to, send, no_response, response_timeout, encrypter)
+        response = do_io(global_opts.to, send, no_response, response_timeout, encrypter, global_opts.retry, io)
     except (TimeoutError, socket.timeout):
         send.pop('payload', None)
         send['cancel_async'] = True
         try:
-            do_io(global_opts.to, send, True, 10, encrypter)
+            do_io(global_opts.to, send, True, 10, encrypter, io=io)
         except KeyboardInterrupt:
             sys.excepthook = lambda *a: print('Interrupted by user', file=sys.stderr)
             raise
         except SocketClosed as e:
             raise SystemExit(str(e))
-        raise SystemExit(f'Timed out after {response_timeout} seconds waiting for response from kitty')
+        raise SystemExit(encrypter.timed_out_message(response_timeout))
     except KeyboardInterrupt:
         sys.excepthook = lambda *a: print('Interrupted by user', file=sys.stderr)
         raise
     except FileNotFoundError:
         raise SystemExit(f'No listen on socket found at: {global_opts.to}')
+    except ConnectionRefusedError:
+        raise SystemExit(f'kitty is not accepting connections at: {global_opts.to}')
     except SocketClosed as e:
         raise SystemExit(str(e))
     if no_response:
@@ -531,3 +668,71 @@ def main(args: List[str]) -> None:
         if c.string_return_is_error and isinstance(data, str):
             raise SystemExit(data)
         print(data)
+
+
+def run_commands_from_stdin(global_opts: RCOptions, encrypter: 'CommandEncrypter') -> None:
+    import shlex
+    io: Optional[Union[PersistentSocketIO, PersistentRCIO]] = None
+    if global_opts.to:
+        io = PersistentSocketIO(global_opts.to, global_opts.retry)
+    elif not sys.stdin.isatty():
+        # the tty cannot be kept in raw mode while commands are typed into it
+        io = PersistentRCIO()
+    failed = False
+    try:
+        for line in sys.stdin:
+            line = line.strip()
+            if not line or line.startswith('#'):
+                continue
+            try:
+                items = shlex.split(line)
+            except ValueError as err:
+                print(f'Invalid command: {line} with error: {err}', file=sys.stderr)
+                failed = True
+                continue
+            try:
+                run_command(global_opts, encrypter, items, io)
+            except SystemExit as e:
+                if e.code:
+                    failed = True
+                    if isinstance(e.code, str):
+                        print(e.code, file=sys.stderr)
+            sys.stdout.flush()
+    finally:
+        if io is not None:
+            io.close()
+    if failed:
+        raise SystemExit(1)
+
+
+def main(args: List[str]) -> None:
+    global_opts, items = parse_rc_args(args)
+    password = get_password(global_opts)
+    if password:
+        encryption_version, pubkey = get_pubkey()
+        encrypter = CommandEncrypter(pubkey, encryption_version, password)
+    else:
+        encrypter = NoEncryption()
+
+    if not items and not global_opts.stdin_commands:
+        from kitty.shell import main as smain
+        smain(global_opts, encrypter)
+        return
+    listen_on_from_env = False
+    if not global_opts.to and 'KITTY_LISTEN_ON' in os.environ:
+        global_opts.to = os.environ['KITTY_LISTEN_ON']
+        listen_on_from_env = False
+    if global_opts.to:
+        try:
+            parse_address_spec(global_opts.to)
+        except Exception:
+            msg = f'Invalid listen on address: {global_opts.to}'
+            if listen_on_from_env:
+                msg += '. The KITTY_LISTEN_ON environment variable is set incorrectly'
+            exit(msg)
+    if global_opts.stdin_commands:
+        if items:
+            raise SystemExit('No command must be specified when using --stdin-commands')
+        run_commands_from_stdin(global_opts, encrypter)
+        return
+    run_command(global_opts, encrypter, items)
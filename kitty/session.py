@@ -1,10 +1,12 @@
 #!/usr/bin/env python3
 # License: GPL v3 Copyright: 2016, Kovid Goyal <kovid at kovidgoyal.net>
 
+import json
 import shlex
 import sys
 from typing import (
-    TYPE_CHECKING, Generator, Iterator, List, Optional, Tuple, Union
+    TYPE_CHECKING, Any, Dict, Generator, Iterator, List, Optional, Tuple,
+    Union
 )
 
 from .cli_stub import CLIOptions
@@ -50,6 +52,7 @@ def __init__(self, opts: Options, name: str):
         self.layout = (self.enabled_layouts or ['tall'])[0]
         self.cwd: Optional[str] = None
         self.next_title: Optional[str] = None
+        self.layout_state: Optional[Dict[str, Any]] = None
 
 
 class Session:
@@ -114,6 +117,9 @@ def set_enabled_layouts(self, raw: str) -> None:
     def set_cwd(self, val: str) -> None:
         self.tabs[-1].cwd = val
 
+    def set_layout_state(self, val: str) -> None:
+        self.tabs[-1].layout_state = {'pairs': json.loads(val)}
+
 
 def parse_session(raw: str, opts: Options) -> Generator[Session, None, None]:
 
@@ -162,6 +168,8 @@ def finalize_session(ans: Session) -> Session:
                 ans.os_window_class = rest
             elif cmd == 'resize_window':
                 ans.resize_window(rest.split())
+            elif cmd == 'layout_state':
+                ans.set_layout_state(rest)
             else:
                 raise ValueError(f'Unknown command in session file: {cmd}')
     yield finalize_session(ans)
@@ -22,9 +22,11 @@
     display_subcommand_help, parse_subcommand_cli
 )
 from .remote_control import (
-    CommandEncrypter, NoEncryption, create_basic_command, do_io
+    CommandEncrypter, NoEncryption, create_basic_command, do_io,
+    response_timeout_for
 )
 from .types import run_once
+from .utils import locked_file
 
 output_prefix = '\x1b]133;C\x1b\\'
 is_libedit = False
@@ -113,7 +115,8 @@ def __enter__(self) -> 'Completer':
 
     def __exit__(self, *a: Any) -> None:
         import readline
-        readline.write_history_file(self.history_path)
+        with locked_file(self.history_path + '.lock'):
+            readline.write_history_file(self.history_path)
 
 
 def print_err(*a: Any, **kw: Any) -> None:
@@ -157,17 +160,14 @@ def run_cmd(
     if hasattr(opts, 'no_response'):
         no_response = opts.no_response
     send = original_send_cmd = create_basic_command(cmd, payload=payload, is_asynchronous=func.is_asynchronous, no_response=no_response)
-    response_timeout = func.response_timeout
-    if hasattr(opts, 'response_timeout'):
-        response_timeout = opts.response_timeout
-    response_timeout = encrypter.adjust_response_timeout_for_password(response_timeout)
+    response_timeout = response_timeout_for(global_opts, func, opts, encrypter)
     try:
-        response = do_io(global_opts.to, send, no_response, response_timeout, encrypter)
+        response = do_io(global_opts.to, send, no_response, response_timeout, encrypter, global_opts.retry)
     except TimeoutError:
         original_send_cmd.pop('payload', None)
         original_send_cmd['cancel_async'] = True
         do_io(global_opts.to, send, True, 10, encrypter)
-        print_err(f'Timed out after {response_timeout} seconds waiting for response from kitty')
+        print_err(encrypter.timed_out_message(response_timeout))
         return
     if not response.get('ok'):
         if response.get('tb'):
@@ -52,6 +52,7 @@ class TabDict(TypedDict):
     layout_opts: Dict[str, Any]
     enabled_layouts: List[str]
     windows: List[WindowDict]
+    groups: List[Dict[str, Any]]
     active_window_history: List[int]
 
 
@@ -168,18 +169,33 @@ def _set_current_layout(self, layout_name: str) -> None:
         self.mark_tab_bar_dirty()
 
     def startup(self, session_tab: 'SessionTab') -> None:
+        created: List[Optional[Window]] = []
         for window in session_tab.windows:
             spec = window.launch_spec
             if isinstance(spec, SpecialWindowInstance):
-                self.new_special_window(spec)
+                created.append(self.new_special_window(spec))
             else:
                 from .launch import launch
-                launch(get_boss(), spec.opts, spec.args, target_tab=self, force_target_tab=True)
+                created.append(launch(get_boss(), spec.opts, spec.args, target_tab=self, force_target_tab=True))
             if window.resize_spec is not None:
                 self.resize_window(*window.resize_spec)
 
+        if session_tab.layout_state is not None and not self.apply_layout_state(session_tab.layout_state, created):
+            log_error('Ignoring layout_state in session file as it does not match the windows in the tab')
         self.windows.set_active_window_group_for(self.windows.all_windows[session_tab.active_window_idx])
 
+    def apply_layout_state(self, layout_state: Dict[str, Any], windows: Sequence[Optional[Window]]) -> bool:
+        # the leaves of layout_state are indices into windows rather than group ids
+        def map_group_id(idx: int) -> Optional[int]:
+            w = windows[idx] if 0 <= idx < len(windows) else None
+            g = None if w is None else self.windows.group_for_window(w.id)
+            return None if g is None else g.id
+
+        if self.current_layout.set_layout_state(self.windows, layout_state, map_group_id):
+            self.relayout()
+            return True
+        return False
+
     def serialize_state(self) -> Dict[str, Any]:
         return {
             'version': 1,
@@ -702,6 +718,10 @@ def list_windows(self, active_window: Optional[Window], self_window: Optional[Wi
         for w in self:
             yield w.as_dict(is_focused=w is active_window, is_self=w is self_window)
 
+    def list_groups(self) -> Generator[Dict[str, Any], None, None]:
+        for g in self.windows.groups:
+            yield {'id': g.id, 'windows': [w.id for w in g]}
+
     def matches_query(self, field: str, query: str, active_tab_manager: Optional['TabManager'] = None) -> bool:
         if field == 'title':
             return re.search(query, self.effective_title) is not None
@@ -945,6 +965,7 @@ def list_tabs(self, active_tab: Optional[Tab], active_window: Optional[Window],
                 'layout_opts': tab.current_layout.layout_opts.serialized(),
                 'enabled_layouts': tab.enabled_layouts,
                 'windows': list(tab.list_windows(active_window, self_window)),
+                'groups': list(tab.list_groups()),
                 'active_window_history': list(tab.windows.active_window_history),
             }
 
@@ -53,14 +53,17 @@ def __bool__(self) -> bool:
 
 def expandvars(val: str, env: Mapping[str, str] = {}, fallback_to_os_env: bool = True) -> str:
     '''
-    Expand $VAR and ${VAR} Use $$ for a literal $
+    Expand $VAR, ${VAR} and ${VAR:-default} Use $$ for a literal $
     '''
 
     def sub(m: 'Match[str]') -> str:
         key = m.group(1) or m.group(2)
+        key, has_default, default = key.partition(':-')
         result = env.get(key)
         if result is None and fallback_to_os_env:
             result = os.environ.get(key)
+        if has_default and not result:
+            result = default
         if result is None:
             result = m.group()
         return result
@@ -990,14 +993,8 @@ def hold_till_enter() -> None:
                 write_all(fd, msg)
 
 
-def cleanup_ssh_control_masters() -> None:
-    import glob
+def close_ssh_control_masters(files: Iterable[str]) -> None:
     import subprocess
-    try:
-        files = frozenset(glob.glob(os.path.join(runtime_dir(), ssh_control_master_template.format(
-            kitty_pid=os.getpid(), ssh_placeholder='*'))))
-    except OSError:
-        return
     workers = tuple(subprocess.Popen([
         'ssh', '-o', f'ControlPath={x}', '-O', 'exit', 'kitty-unused-host-name'], stdout=subprocess.DEVNULL, stderr=subprocess.DEVNULL,
         preexec_fn=clear_handled_signals) for x in files)
@@ -1008,6 +1005,53 @@ def cleanup_ssh_control_masters() -> None:
             os.remove(x)
 
 
+def cleanup_ssh_control_masters() -> None:
+    import glob
+    try:
+        files = frozenset(glob.glob(os.path.join(runtime_dir(), ssh_control_master_template.format(
+            kitty_pid=os.getpid(), ssh_placeholder='*'))))
+    except OSError:
+        return
+    close_ssh_control_masters(files)
+
+
+def cleanup_stale_ssh_control_masters() -> None:
+    ' Close shared connections left behind by kitty instances that did not exit cleanly '
+    import glob
+    pat = re.compile(ssh_control_master_template.format(kitty_pid=r'(\d+)', ssh_placeholder='.+'))
+    try:
+        candidates = glob.glob(os.path.join(runtime_dir(), ssh_control_master_template.format(kitty_pid='*', ssh_placeholder='*')))
+    except OSError:
+        return
+    stale = []
+    for x in candidates:
+        m = pat.fullmatch(os.path.basename(x))
+        if m is not None:
+            try:
+                os.kill(int(m.group(1)), 0)
+            except ProcessLookupError:
+                stale.append(x)
+            except OSError:
+                pass
+    if stale:
+        close_ssh_control_masters(stale)
+
+
+@contextmanager
+def locked_file(path: str) -> Generator[int, None, None]:
+    '''
+    Hold an exclusive advisory lock on path, creating it if needed, for the
+    duration of the with block. The lock is released by the OS if the process
+    dies, so it can never be left stale.
+    '''
+    fd = os.open(path, os.O_CREAT | os.O_RDWR | os.O_CLOEXEC, 0o600)
+    try:
+        fcntl.lockf(fd, fcntl.LOCK_EX)
+        yield fd
+    finally:
+        os.close(fd)
+
+
 def path_from_osc7_url(url: str) -> str:
     if url.startswith('kitty-shell-cwd://'):
         return '/' + url.split('/', 3)[-1]
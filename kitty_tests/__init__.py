@@ -3,6 +3,7 @@
 
 import fcntl
 import io
+import json
 import os
 import select
 import shlex
@@ -66,6 +67,9 @@ def open_url(self, url: str, hyperlink_id: int) -> None:
     def clipboard_control(self, data: str, is_partial: bool = False) -> None:
         self.cc_buf.append((data, is_partial))
 
+    def handle_remote_cmd(self, cmd: str) -> None:
+        self.rc_cmds.append(json.loads(cmd))
+
     def clear(self) -> None:
         self.wtcbuf = b''
         self.iconbuf = self.colorbuf = self.ctbuf = ''
@@ -74,6 +78,7 @@ def clear(self) -> None:
         self.notifications = []
         self.open_urls = []
         self.cc_buf = []
+        self.rc_cmds = []
         self.bell_count = 0
         self.clone_cmds = []
         self.current_clone_data = ''
@@ -181,6 +186,10 @@ def create_screen(self, cols=5, lines=5, scrollback=5, cell_width=10, cell_heigh
         s = Screen(c, lines, cols, scrollback, cell_width, cell_height, 0, c)
         return s
 
+    def create_tui_loop(self, handler, cols=80, lines=25, cell_width=10, cell_height=20, options=None):
+        self.set_options(options)
+        return TUILoop(handler, cols, lines, cell_width, cell_height)
+
     def create_pty(
             self, argv=None, cols=80, lines=100, scrollback=100, cell_width=10, cell_height=20,
             options=None, cwd=None, env=None, stdin_fd=None, stdout_fd=None
@@ -316,3 +325,92 @@ def screen_contents(self):
     def last_cmd_output(self, as_ansi=False, add_wrap_markers=False):
         from kitty.window import cmd_output
         return cmd_output(self.screen, as_ansi=as_ansi, add_wrap_markers=add_wrap_markers)
+
+
+class TUILoop:
+
+    ' Runs a kittens.tui Handler against an in-memory Screen instead of a tty, for testing '
+
+    def __init__(self, handler, cols=80, lines=25, cell_width=10, cell_height=20):
+        from kittens.tui.loop import Debug
+        self.callbacks = Callbacks()
+        self.screen = Screen(self.callbacks, lines, cols, 0, cell_width, cell_height, 0, self.callbacks)
+        self.cell_width, self.cell_height = cell_width, cell_height
+        self.handler = handler
+        self.return_code = 0
+        self.quit_requested = False
+        self.pending_calls = []
+        # the handler uses this for call_soon() and call_later()
+        self.asyncio_loop = self
+        handler._initialize(self.screen_size(), None, self.write, self, Debug())
+
+    def screen_size(self):
+        from kitty.utils import ScreenSize
+        s = self.screen
+        return ScreenSize(s.lines, s.columns, s.columns * self.cell_width, s.lines * self.cell_height, self.cell_width, self.cell_height)
+
+    def __enter__(self):
+        self.handler.__enter__()
+        self.run_pending()
+        return self
+
+    def __exit__(self, *a):
+        self.handler.__exit__(*a)
+
+    def write(self, data):
+        parse_bytes(self.screen, data)
+
+    def quit(self, return_code=None):
+        if return_code is not None:
+            self.return_code = return_code
+        self.quit_requested = True
+
+    def call_soon(self, callback, *args):
+        self.pending_calls.append((callback, args))
+
+    call_soon_threadsafe = call_soon
+
+    def call_later(self, delay, callback, *args):
+        self.pending_calls.append((callback, args))
+
+    def add_writer(self, fd, callback, *args):
+        pass
+
+    def remove_writer(self, fd):
+        pass
+
+    def run_pending(self):
+        while self.pending_calls:
+            callback, args = self.pending_calls.pop(0)
+            callback(*args)
+
+    def resize(self, cols, lines):
+        self.screen.resize(lines, cols)
+        self.handler.on_resize(self.screen_size())
+        self.run_pending()
+
+    def rc_cmds(self):
+        ans, self.callbacks.rc_cmds = self.callbacks.rc_cmds, []
+        return ans
+
+    def send_cmd_response(self, response):
+        self.handler.on_kitty_cmd_response(response)
+        self.run_pending()
+
+    def screen_lines(self):
+        return [str(self.screen.line(i)).rstrip() for i in range(self.screen.lines)]
+
+    def terminal_input_loop(self):
+        ' A kittens.tui Loop to parse raw terminal input, that schedules its calls on this loop '
+        import asyncio
+
+        from kittens.tui.loop import Loop
+        el = asyncio.new_event_loop()
+        asyncio.set_event_loop(el)
+        try:
+            ans = Loop()
+        finally:
+            asyncio.set_event_loop(None)
+            el.close()
+        ans.asyncio_loop = self
+        return ans
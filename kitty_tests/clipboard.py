@@ -0,0 +1,64 @@
+#!/usr/bin/env python3
+# License: GPLv3 Copyright: 2022, Kovid Goyal <kovid at kovidgoyal.net>
+
+import os
+import sys
+from contextlib import contextmanager
+from threading import Thread
+from unittest.mock import patch
+
+from . import BaseTest
+
+
+class TestClipboard(BaseTest):
+
+    def test_run_filter(self):
+        from kittens.clipboard.main import run_filter
+        self.ae(run_filter('tr a-z A-Z', b'abc\n'), b'ABC\n')
+        self.ae(run_filter(f'{sys.executable} -c "import sys; sys.stdout.write(sys.stdin.read()[::-1])"', 'xyé'.encode('utf-8')), 'éyx'.encode('utf-8'))
+        self.ae(run_filter('true', b'ignored'), b'')
+        with self.assertRaisesRegex(SystemExit, 'exit code: 3'):
+            run_filter(f'{sys.executable} -c "raise SystemExit(3)"', b'')
+        with self.assertRaisesRegex(SystemExit, 'Failed to run the filter command'):
+            run_filter('/nonexistent/kitty-clipboard-filter', b'')
+
+    def test_watch_stays_in_raw_mode(self):
+        import kittens.clipboard.main as m
+        master, slave = os.openpty()
+        raw_mode_entered = []
+        outputs = []
+
+        real_raw_mode = m.raw_mode
+
+        @contextmanager
+        def raw_mode(fd):
+            raw_mode_entered.append(fd)
+            with real_raw_mode(fd):
+                yield
+
+        def output(filter_cmd):
+            outputs.append(m.clipboard_contents)
+            # interrupt the watch while it is waiting between polls
+            os.write(master, b'\x03')
+
+        def respond():
+            # answer the first clipboard request, only once it has been sent,
+            # as entering raw mode discards any pending input
+            received = b''
+            while b'\x1b]52;' not in received:
+                received += os.read(master, 4096)
+            os.write(master, b'\x1b]52;c;YWJj\x1b\\')
+
+        t = Thread(target=respond, daemon=True)
+        t.start()
+        try:
+            with open(slave, 'wb', closefd=False) as ttyf, patch.object(m, 'raw_mode', raw_mode), patch.object(m, 'output_clipboard_contents', output):
+                self.assertRaises(KeyboardInterrupt, m.watch_clipboard, slave, ttyf, False, 0.05, 'cat')
+            t.join()
+        finally:
+            os.close(master)
+            os.close(slave)
+            m.clipboard_contents = ''
+            m.got_clipboard_response = False
+        self.ae(outputs, ['abc'])
+        self.ae(raw_mode_entered, [slave])
@@ -12,7 +12,7 @@
     wcwidth
 )
 from kitty.rgb import to_color
-from kitty.utils import is_path_in_temp_dir, sanitize_title
+from kitty.utils import expandvars, is_path_in_temp_dir, sanitize_title
 
 from . import BaseTest, filled_cursor, filled_history_buf, filled_line_buf
 
@@ -437,6 +437,11 @@ def on_csi(x):
         for path in ('/home/xy/d.png', '/tmp/../home/x.jpg'):
             self.assertFalse(is_path_in_temp_dir(os.path.join(path)))
 
+        env = {'A': 'a', 'E': ''}
+        self.ae(expandvars('$A/${A}/$$A', env, fallback_to_os_env=False), 'a/a/$A')
+        self.ae(expandvars('${A:-x}/${E:-y}/${U:-z}/${U:-}', env, fallback_to_os_env=False), 'a/y/z/')
+        self.ae(expandvars('$U/${U}', env, fallback_to_os_env=False), '$U/${U}')
+
     def test_color_profile(self):
         c = ColorProfile()
         c.update_ansi_color_table(build_ansi_color_table())
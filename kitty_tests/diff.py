@@ -83,3 +83,119 @@ def test_walk(self):
             walk(tmpdir, names, pmap, ("*~", "#*#", "b"))
             self.ae(expected_names, names)
             self.ae(expected_pmap, pmap)
+
+    def test_rename_detection(self):
+        from pathlib import Path
+        import tempfile
+        from kittens.diff.collect import Collection, collect_files
+
+        with tempfile.TemporaryDirectory() as left, tempfile.TemporaryDirectory() as right:
+            for name in ('a', 'b'):
+                Path(left, name).write_text('same contents\n')
+            for name in ('c', 'd'):
+                Path(right, name).write_text('same contents\n')
+            Path(left, 'e').write_text('removed\n')
+            c = Collection()
+            collect_files(c, left, right)
+            self.ae(set(c.renames), {f'{left}/a', f'{left}/b'})
+            self.ae(set(c.renames.values()), {f'{right}/c', f'{right}/d'})
+            self.ae(c.removes, {f'{left}/e'})
+            self.ae(c.adds, set())
+
+    def test_git_revspec(self):
+        from kittens.diff.collect import add_remote_dir, remote_dirs, resolve_remote_name
+        from kittens.diff.main import parse_git_revspec
+
+        def p(*revspec, staged=False):
+            return parse_git_revspec(revspec, staged, lambda a, b: f'base({a},{b})')
+
+        self.ae(p(), ('', None))
+        self.ae(p('A..B'), ('A', 'B'))
+        self.ae(p('A...B'), ('base(A,B)', 'B'))
+        self.ae(p('..B'), ('HEAD', 'B'))
+        self.ae(p('A', 'B'), ('A', 'B'))
+        self.ae(p('origin/main'), ('origin/main', None))
+        self.ae(p('feature/x..HEAD'), ('feature/x', 'HEAD'))
+        self.ae(p(staged=True), ('HEAD', ''))
+        self.ae(p('origin/main', staged=True), ('origin/main', ''))
+        self.assertRaises(SystemExit, p, 'A..B', staged=True)
+        self.assertRaises(SystemExit, p, 'A', 'B', 'C')
+        add_remote_dir('/some/dir', 'origin/main')
+        try:
+            self.ae(resolve_remote_name('/some/dir/a/b', 'x'), 'origin/main:a/b')
+        finally:
+            del remote_dirs['/some/dir']
+
+    def test_git_dirs(self):
+        import os
+        import shutil
+        import subprocess
+        import tempfile
+
+        from kittens.diff.collect import remote_dirs
+        from kittens.diff.main import get_git_dirs
+        if not shutil.which('git'):
+            self.skipTest('git not available')
+
+        def git(*args, cwd=''):
+            return subprocess.run(
+                ['git', '-c', 'user.name=x', '-c', 'user.email=x@x', '-C', cwd or tdir] + list(args),
+                check=True, stdout=subprocess.PIPE, stderr=subprocess.DEVNULL).stdout.decode('utf-8').strip()
+
+        def contents(base):
+            ans = {}
+            for dirpath, dirnames, filenames in os.walk(base):
+                for x in filenames:
+                    path = os.path.join(dirpath, x)
+                    with open(path) as f:
+                        ans[os.path.relpath(path, base)] = f.read()
+            return ans
+
+        def dirs(*revspec, staged=False):
+            cwd = os.getcwd()
+            os.chdir(tdir)
+            try:
+                left, right, title = get_git_dirs(revspec, staged)
+            finally:
+                os.chdir(cwd)
+            remote_dirs.pop(left, None), remote_dirs.pop(right, None)
+            return contents(left), contents(right), title
+
+        with tempfile.TemporaryDirectory() as tdir:
+            git('init', '-q')
+            with open(os.path.join(tdir, 'a'), 'w') as f:
+                f.write('one\n')
+            git('add', 'a')
+            git('commit', '-q', '-m', '1')
+            first = git('rev-parse', 'HEAD')
+            with open(os.path.join(tdir, 'a'), 'w') as f:
+                f.write('two\n')
+            # a submodule that is not checked out, pointing at the first commit
+            git('update-index', '--add', '--cacheinfo', f'160000,{first},sub')
+            git('add', 'a')
+            git('commit', '-q', '-m', '2')
+            second = git('rev-parse', 'HEAD')
+            self.ae(dirs('HEAD~1..HEAD'), (
+                {'a': 'one\n'}, {'a': 'two\n', 'sub': f'Subproject commit {first}\n'}, 'HEAD~1 vs. HEAD'))
+            git('update-index', '--cacheinfo', f'160000,{second},sub')
+            self.ae(dirs(staged=True), (
+                {'sub': f'Subproject commit {first}\n'}, {'sub': f'Subproject commit {second}\n'}, 'HEAD vs. index'))
+            with open(os.path.join(tdir, 'a'), 'w') as f:
+                f.write('three\n')
+            # git reports the submodule as deleted since it is not checked out
+            self.ae(dirs(), (
+                {'a': 'two\n', 'sub': f'Subproject commit {second}\n'}, {'a': 'three\n'}, 'index vs. working tree'))
+
+            # a checked out submodule, whose HEAD has moved on
+            git('update-index', '--force-remove', 'sub')
+            sub = os.path.join(tdir, 'sub')
+            os.mkdir(sub)
+            git('init', '-q', cwd=sub)
+            git('commit', '-q', '--allow-empty', '-m', 's1', cwd=sub)
+            s1 = git('rev-parse', 'HEAD', cwd=sub)
+            git('add', 'sub')
+            git('commit', '-q', '--allow-empty', '-m', 's2', cwd=sub)
+            s2 = git('rev-parse', 'HEAD', cwd=sub)
+            self.ae(dirs(), (
+                {'a': 'two\n', 'sub': f'Subproject commit {s1}\n'},
+                {'a': 'three\n', 'sub': f'Subproject commit {s2}\n'}, 'index vs. working tree'))
@@ -16,7 +16,9 @@
 from kittens.transfer.receive import File, files_for_receive
 from kittens.transfer.rsync import decode_utf8_buffer, parse_ftc
 from kittens.transfer.send import files_for_send
-from kittens.transfer.utils import cwd_path, expand_home, home_path, set_paths
+from kittens.transfer.utils import (
+    cwd_path, expand_home, has_compressed_magic, home_path, set_paths
+)
 from kitty.file_transmission import (
     Action, Compression, FileTransmissionCommand, FileType,
     TestFileTransmission as FileTransmission, TransmissionType,
@@ -480,3 +482,54 @@ def tf(args, expected):
             files = gm(b / 'h', b / 'r', 'dest')
             self.ae(files[1].file_type, FileType.link)
             self.ae(files[1].hard_link_target, '1')
+
+    def test_compression_choice(self):
+        b = Path(os.path.join(self.tdir, 'b'))
+        os.makedirs(b)
+        large_text = b'some text\n' * 1000
+        files = {
+            'small.txt': b'small',
+            'large.txt': large_text,
+            'archive.gz': large_text,
+            'data.bin': b'\x1f\x8b\x08\x00' + large_text,
+            'photo': b'\x89PNG\r\n\x1a\n' + large_text,
+            'movie': b'\0\0\0\x18ftypmp42' + large_text,
+            'image': b'RIFF\0\0\0\0WEBP' + large_text,
+            'sound.wav': b'RIFF\0\0\0\0WAVE' + large_text,
+            'empty': b'',
+        }
+        for name, data in files.items():
+            with open(b / name, 'wb') as f:
+                f.write(data)
+
+        self.assertTrue(has_compressed_magic(str(b / 'data.bin')))
+        self.assertTrue(has_compressed_magic(str(b / 'photo')))
+        self.assertTrue(has_compressed_magic(str(b / 'movie')))
+        self.assertTrue(has_compressed_magic(str(b / 'image')))
+        self.assertFalse(has_compressed_magic(str(b / 'sound.wav')))
+        self.assertFalse(has_compressed_magic(str(b / 'large.txt')))
+        self.assertFalse(has_compressed_magic(str(b / 'empty')))
+        self.assertFalse(has_compressed_magic(str(b / 'does-not-exist')))
+
+        def sent(compress):
+            opts = parse_transfer_args(['transfer', f'--compress={compress}'])[0]
+            with set_paths(cwd=b, home='/foo/bar'):
+                return {os.path.basename(f.expanded_local_path): f.compression_capable for f in files_for_send(opts, list(files) + ['/dest'])}
+
+        self.ae(sent('auto'), {
+            'small.txt': False, 'large.txt': True, 'archive.gz': False, 'data.bin': False,
+            'photo': False, 'movie': False, 'image': False, 'sound.wav': True, 'empty': False,
+        })
+        self.ae(sent('always'), {k: True for k in files})
+        self.ae(sent('never'), {k: False for k in files})
+
+        def received(compress, name, size):
+            f = File(FileTransmissionCommand(file_id='1', name=name, size=size), compress)
+            return isinstance(f.decompressor, ZlibDecompressor)
+
+        self.assertTrue(received('auto', '/src/large.txt', 10000))
+        self.assertFalse(received('auto', '/src/small.txt', 100))
+        self.assertFalse(received('auto', '/src/archive.gz', 10000))
+        self.assertTrue(received('always', '/src/archive.gz', 10000))
+        self.assertTrue(received('always', '/src/small.txt', 100))
+        self.assertFalse(received('never', '/src/large.txt', 10000))
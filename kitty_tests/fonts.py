@@ -184,3 +184,27 @@ def test_coalesce_symbol_maps(self):
         q = {(0, 30): 'a', (10, 10): 'b', (11, 11): 'b', (2, 2): 'c', (1, 1): 'c'}
         self.ae(coalesce_symbol_maps(q), {
             (0, 0): 'a', (1, 2): 'c', (3, 9): 'a', (10, 11): 'b', (12, 30): 'a'})
+
+    def test_get_fonts_response(self):
+        import json
+        from types import SimpleNamespace
+        from unittest.mock import patch
+
+        from kitty.fast_data_types import current_fonts
+        from kitty.rc.get_fonts import get_fonts
+        self.assertRaises(KeyError, current_fonts, 12345)
+        cf = current_fonts()
+        requested = []
+
+        def fonts_for(os_window_id=0):
+            requested.append(os_window_id)
+            return cf
+
+        with patch('kitty.fast_data_types.current_fonts', fonts_for), patch('kitty.fast_data_types.os_window_font_size', lambda os_window_id: 11.5):
+            window = SimpleNamespace(os_window_id=7)
+            ans = json.loads(get_fonts.response_from_kitty(SimpleNamespace(active_window=None), window, lambda key: None))
+        self.ae(requested, [7])
+        self.ae(sorted(ans), ['bold', 'bold_italic', 'fallback', 'font_size', 'italic', 'regular'])
+        self.ae(ans['font_size'], 11.5)
+        self.ae(ans['regular'], cf['medium'].display_name())
+        self.ae(ans['fallback'], [f.display_name() for f in cf['fallback']])
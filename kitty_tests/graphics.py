@@ -421,6 +421,24 @@ def test_load_png_simple(self):
         # test error handling for loading bad png data
         self.assertRaisesRegex(ValueError, '[EBADPNG]', load_png_data, b'dsfsdfsfsfd')
 
+    def test_tmux_passthrough(self):
+        from kittens.icat.main import tmux_passthrough
+        from kittens.tui.images import GraphicsCommand
+        cmd = GraphicsCommand()
+        cmd.f, cmd.s, cmd.v, cmd.i = 24, 1, 1, 1
+        raw = cmd.serialize(standard_b64encode(b'abc'))
+        wrapped = tmux_passthrough(raw)
+        self.assertTrue(wrapped.startswith(b'\033Ptmux;\033\033_G'))
+        self.assertTrue(wrapped.endswith(b'\033\033\\\033\\'))
+        body = wrapped[len(b'\033Ptmux;'):-2]
+        # every ESC inside the DCS is doubled so that it cannot terminate it early
+        self.assertNotIn(b'\033', body.replace(b'\033\033', b''))
+        unwrapped = body.replace(b'\033\033', b'\033')
+        self.ae(unwrapped, raw)
+        s = self.create_screen()
+        parse_bytes(s, unwrapped)
+        self.ae(s.grman.image_count, 1)
+
     def test_gr_operations_with_numbers(self):
         s = self.create_screen()
         g = s.grman
@@ -1,9 +1,12 @@
 #!/usr/bin/env python3
 # License: GPL v3 Copyright: 2018, Kovid Goyal <kovid at kovidgoyal.net>
 
+from types import SimpleNamespace
+
 from kitty.config import defaults
 from kitty.types import WindowGeometry
 from kitty.layout.interface import Grid, Horizontal, Splits, Stack, Tall
+from kitty.layout.splits import Pair
 from kitty.window import EdgeWidths
 from kitty.window_list import WindowList, reset_group_id_counter
 
@@ -21,6 +24,8 @@ def __init__(self, win_id, overlay_for=None, overlay_window_id=None):
         self.padding = EdgeWidths()
         self.margin = EdgeWidths()
         self.focused = False
+        self.override_title = None
+        self.child = SimpleNamespace(argv=['vim'], cwd='/tmp', current_cwd=None, env={})
 
     def focus_changed(self, focused):
         self.focused = focused
@@ -50,6 +55,50 @@ def create_layout(cls, opts=None, border_width=2):
     return ans
 
 
+def export_splits_session(layout, all_windows):
+    from kitty.rc.export_session import export_session
+
+    class TabManager(list):
+        wm_class = ''
+
+    tab = SimpleNamespace(name='', current_layout=layout, enabled_layouts=['splits', 'stack'], windows=all_windows)
+    tm = TabManager([tab])
+    tm.active_tab = tab
+    return export_session.response_from_kitty(SimpleNamespace(os_window_map={1: tm}), None, lambda key: None)
+
+
+def restore_splits_session(session_tab):
+    # does what kitty.tabs.Tab.startup() does with windows created in reverse order
+    from kitty.tabs import Tab as KittyTab
+    layout = create_layout(Splits)
+    all_windows = create_windows(layout, num=0)
+    created = [Window(i + 100) for i in range(len(session_tab.windows))]
+    for w in reversed(created):
+        layout.add_window(all_windows, w)
+        layout(all_windows)
+    tab = SimpleNamespace(windows=all_windows, current_layout=layout, relayout=lambda: layout(all_windows))
+    return KittyTab.apply_layout_state(tab, session_tab.layout_state, created), layout, all_windows, created
+
+
+def splits_tree_by_index(layout, all_windows, windows=None):
+    # the splits tree with group ids replaced by the index of the window in windows,
+    # which defaults to the groups in order, as export-session numbers them
+    if windows is None:
+        index_of = {g.id: i for i, g in enumerate(all_windows.groups)}
+    else:
+        index_of = {all_windows.group_for_window(w.id).id: i for i, w in enumerate(windows)}
+
+    def convert(state):
+        ans = dict(state)
+        for attr in ('one', 'two'):
+            if isinstance(ans.get(attr), dict):
+                ans[attr] = convert(ans[attr])
+            elif attr in ans:
+                ans[attr] = index_of[ans[attr]]
+        return ans
+    return convert(layout.layout_state()['pairs'])
+
+
 class Tab:
 
     def active_window_changed(self):
@@ -257,3 +306,74 @@ def test_splits(self):
         self.ae(q.neighbors_for_window(windows[1], all_windows), {'left': [1], 'right': [], 'top': [], 'bottom': [3, 4]})
         self.ae(q.neighbors_for_window(windows[2], all_windows), {'left': [1], 'right': [4], 'top': [2], 'bottom': []})
         self.ae(q.neighbors_for_window(windows[3], all_windows), {'left': [3], 'right': [], 'top': [2], 'bottom': []})
+
+    def test_splits_layout_state(self):
+        q = create_layout(Splits)
+        all_windows = create_windows(q, num=0)
+        for i, location in enumerate((None, 'vsplit', 'hsplit')):
+            q.add_window(all_windows, Window(i + 1), location=location)
+            q(all_windows)
+        q.pairs_root.bias = 0.7
+        state = q.layout_state()
+        gids = [g.id for g in all_windows.groups]
+
+        r = create_layout(Splits)
+        other_windows = create_windows(r, num=0)
+        for i in range(3):
+            r.add_window(other_windows, Window(i + 10))
+        r(other_windows)
+        ogids = [g.id for g in other_windows.groups]
+        self.assertTrue(r.set_layout_state(other_windows, state, lambda gid: ogids[gids.index(gid)]))
+        self.ae(r.layout_state(), {'pairs': {
+            'horizontal': True, 'bias': 0.7, 'one': ogids[0],
+            'two': {'horizontal': False, 'bias': 0.5, 'one': ogids[1], 'two': ogids[2]}}})
+        r(other_windows)
+        self.assertFalse(r.set_layout_state(other_windows, state, lambda gid: None))
+        self.assertFalse(r.set_layout_state(other_windows, {'pairs': {'one': ogids[0], 'two': ogids[1]}}, lambda gid: gid))
+        self.assertFalse(r.set_layout_state(other_windows, {}, lambda gid: gid))
+
+        # a pair whose first child is empty must still report its second child
+        root = r.pairs_root = Pair(horizontal=False)
+        root.two = ogids[0]
+        self.ae(r.layout_state(), {'pairs': {'horizontal': False, 'bias': 0.5, 'two': ogids[0]}})
+
+    def test_splits_session_round_trip(self):
+        from kitty.session import parse_session
+        self.set_options()
+        q = create_layout(Splits)
+        all_windows = create_windows(q, num=0)
+        for i, location in enumerate((None, 'vsplit', 'hsplit')):
+            q.add_window(all_windows, Window(i + 1), location=location)
+            q(all_windows)
+        q.pairs_root.bias = 0.7
+        session = next(parse_session(export_splits_session(q, all_windows), defaults))
+        self.ae(len(session.tabs), 1)
+        stab = session.tabs[0]
+        self.ae(stab.layout, 'splits')
+        self.ae(len(stab.windows), 3)
+        self.assertIsNotNone(stab.layout_state)
+        ok, r, other_windows, created = restore_splits_session(stab)
+        self.assertTrue(ok)
+        # windows are created in reverse so group ids do not match the indices in the session
+        self.ae([other_windows.group_for_window(w.id).id for w in created], [3, 2, 1])
+        self.ae(splits_tree_by_index(r, other_windows, created), splits_tree_by_index(q, all_windows))
+        self.ae(r.layout_state()['pairs']['bias'], 0.7)
+        stab.windows.pop()
+        self.assertFalse(restore_splits_session(stab)[0])
+
+    def test_list_groups(self):
+        from kitty.tabs import Tab as KittyTab
+        self.set_options()
+        q = create_layout(Splits)
+        all_windows = create_windows(q, num=0)
+        for i, location in enumerate((None, 'vsplit')):
+            q.add_window(all_windows, Window(i + 1), location=location)
+            q(all_windows)
+        overlay = Window(3, overlay_for=1)
+        all_windows.add_window(overlay, group_of=1)
+        q(all_windows)
+        tab = SimpleNamespace(windows=all_windows)
+        self.ae(list(KittyTab.list_groups(tab)), [{'id': 1, 'windows': [1, 3]}, {'id': 2, 'windows': [2]}])
+        # overlays are not exported as windows of their own
+        session = export_splits_session(q, all_windows)
+        self.ae(len([line for line in session.splitlines() if line.startswith('launch ')]), 2)
@@ -42,6 +42,251 @@ def test_line_edit(self):
         le.backspace()
         self.assertTrue(le.pending_bell)
 
+    def test_line_edit_word_movement(self):
+        from kittens.tui.line_edit import LineEdit, shell_token_spans
+        le = LineEdit()
+        le.on_text('one two_three  fo-ur', False)
+        positions = []
+        while le.word_left():
+            positions.append(le.cursor_pos)
+        self.ae(positions, [18, 15, 8, 4, 0])
+        self.assertTrue(le.pending_bell)
+        positions = []
+        while le.word_right():
+            positions.append(le.cursor_pos)
+        self.ae(positions, [3, 7, 13, 17, 20])
+        le = LineEdit(word_chars='_-')
+        le.on_text('one two_three  fo-ur', False), le.home()
+        le.word_right(2)
+        self.ae(le.cursor_pos, 13)
+        le.word_right()
+        self.ae(le.cursor_pos, 20)
+        text = 'git commit -m "a b c" it\\ s \'x y\''
+        self.ae([text[s:e] for s, e in shell_token_spans(text)], ['git', 'commit', '-m', '"a b c"', 'it\\ s', "'x y'"])
+        le = LineEdit(shell_tokens=True)
+        le.on_text(text, False)
+        le.word_left(2)
+        self.ae(le.cursor_pos, 22)
+
+    def test_parse_ansi_text(self):
+        from kittens.tui.screen_text import Style, parse_ansi_text, row_as_text
+        rows = parse_ansi_text(
+            'a\x1b[1;31mb\x1b[22;38:2:1:2:3m\x1b]8;id=1;https://x.org\x1b\\c\x1b]8;;\x1b\\\x1b[m文e\u0301\r\n'
+            '\x1b[38;5;200;48;2;4;5;6;4:3mz\tq')
+        self.ae(len(rows), 2)
+        self.ae([c.text for c in rows[0]], ['a', 'b', 'c', '文', '', 'e\u0301'])
+        self.ae(rows[0][1].style, Style(fg=1, bold=True))
+        self.ae(rows[0][2].style, Style(fg=(1, 2, 3)))
+        self.ae([c.hyperlink for c in rows[0][1:4]], ['', 'https://x.org', ''])
+        self.ae(row_as_text(rows[0]), ('abc文e\u0301', [0, 1, 2, 3, 5, 5]))
+        self.ae(len(rows[1]), 9)
+        self.ae(rows[1][8].text, 'q')
+        self.ae(rows[1][8].style, Style(fg=200, bg=(4, 5, 6), decoration=3))
+
+    def test_layout_designer(self):
+        import json
+        from kittens.layout_designer.main import (
+            OPTIONS, LayoutDesigner, LayoutDesignerCLIOptions
+        )
+        from kitty.cli import parse_args
+        opts = parse_args([], OPTIONS, '', '', 'layout_designer', result_class=LayoutDesignerCLIOptions)[0]
+        tab = {
+            'id': 1, 'layout': 'splits', 'layout_state': {'pairs': {'horizontal': True, 'bias': 0.5, 'one': 1, 'two': 2}},
+            'windows': [{'id': 1, 'title': 'left', 'is_self': True}, {'id': 2, 'title': 'right', 'is_self': False}],
+            'groups': [{'id': 1, 'windows': [1]}, {'id': 2, 'windows': [2]}],
+        }
+        with self.create_tui_loop(LayoutDesigner(opts), cols=40, lines=12) as loop:
+            self.ae([c['cmd'] for c in loop.rc_cmds()], ['ls'])
+            loop.send_cmd_response({'ok': True, 'data': json.dumps([{'tabs': [tab]}])})
+            lines = loop.screen_lines()
+            self.ae(lines[2], '┌' + '─' * 18 + '┐┌' + '─' * 18 + '┐')
+            self.assertIn('left', lines[4])
+            self.assertIn('right', lines[4])
+            self.ae(loop.handler.selected_group, 1)
+            loop.handler.on_text('l')
+            self.ae(loop.handler.selected_group, 2)
+            loop.handler.on_text('H')
+            cmds = loop.rc_cmds()
+            self.ae([c['cmd'] for c in cmds], ['focus-window', 'action', 'focus-window', 'ls'])
+            self.ae(cmds[1]['payload'], {'action': 'move_window left', 'match': 'id:2'})
+            loop.handler.on_text('q')
+            self.assertTrue(loop.quit_requested)
+
+    def test_layout_designer_save_and_load(self):
+        import json
+        import os
+        import tempfile
+
+        from kittens.layout_designer.main import (
+            OPTIONS, LayoutDesigner, LayoutDesignerCLIOptions
+        )
+        from kitty.cli import parse_args
+        from kitty.config import defaults
+        from kitty.layout.interface import Splits
+        from kitty.session import parse_session
+
+        from .layout import (
+            Window, create_layout, create_windows, export_splits_session,
+            restore_splits_session, splits_tree_by_index
+        )
+        q = create_layout(Splits)
+        all_windows = create_windows(q, num=0)
+        for i, location in enumerate((None, 'vsplit', 'hsplit')):
+            q.add_window(all_windows, Window(i + 1), location=location)
+            q(all_windows)
+        # what R does in the designer, on the last window
+        q.layout_action('rotate', ('180',), all_windows)
+        q.pairs_root.bias = 0.3
+        with tempfile.TemporaryDirectory() as tdir:
+            path = os.path.join(tdir, 'session.conf')
+            opts = parse_args(['--session-file', path], OPTIONS, '', '', 'layout_designer', result_class=LayoutDesignerCLIOptions)[0]
+            tab = {
+                'id': 1, 'layout': 'splits', 'layout_state': q.layout_state(),
+                'windows': [{'id': w.id, 'title': str(w.id), 'is_self': w.id == 1} for w in all_windows],
+                'groups': [{'id': g.id, 'windows': [w.id for w in g]} for g in all_windows.groups],
+            }
+            with self.create_tui_loop(LayoutDesigner(opts), cols=40, lines=12) as loop:
+                loop.rc_cmds()
+                loop.send_cmd_response({'ok': True, 'data': json.dumps([{'tabs': [tab]}])})
+                loop.handler.on_text('e')
+                cmds = loop.rc_cmds()
+                self.ae([c['cmd'] for c in cmds], ['export-session'])
+                self.ae(cmds[0]['payload'], {'match_tab': 'id:1'})
+                loop.send_cmd_response({'ok': True, 'data': export_splits_session(q, all_windows)})
+                self.assertIn('Session saved to:', '\n'.join(loop.screen_lines()))
+            with open(path) as f:
+                session = next(parse_session(f.read(), defaults))
+        ok, r, other_windows, created = restore_splits_session(session.tabs[0])
+        self.assertTrue(ok)
+        self.ae(splits_tree_by_index(r, other_windows, created), splits_tree_by_index(q, all_windows))
+
+    def test_input_recording(self):
+        import os
+        import tempfile
+
+        from kittens.tui.handler import Handler
+
+        class Echo(Handler):
+
+            def initialize(self):
+                self.received = []
+
+            def on_text(self, text, in_bracketed_paste=False):
+                self.received.append(text)
+
+            def on_key(self, key_event):
+                self.received.append(key_event.key)
+
+        h = Echo()
+        with tempfile.TemporaryDirectory() as tdir, self.create_tui_loop(h) as loop:
+            path = os.path.join(tdir, 'input')
+            tl = loop.terminal_input_loop()
+            tl.record_input(path)
+            r, w = os.pipe()
+            try:
+                for chunk in (b'ab', b'\r', 'c\u00e9'.encode('utf-8')):
+                    os.write(w, chunk)
+                    tl._read_ready(h, r)
+            finally:
+                os.close(r), os.close(w)
+                tl.input_recording.close()
+            self.ae(h.received, ['ab', 'ENTER', 'c\u00e9'])
+            recorded, h.received = h.received, []
+            loop.terminal_input_loop().replay_input(h, path, speed=0)
+            loop.run_pending()
+            self.ae(h.received, recorded)
+
+    def test_focus_events(self):
+        import os
+
+        from kittens.tui.handler import Handler
+        from kitty.fast_data_types import Color
+
+        class Focus(Handler):
+            focus_tracking = True
+
+            def initialize(self):
+                self.changes = []
+                self.focus_dimmed_colors = Color(0, 0, 0), Color(200, 200, 200), Color(100, 0, 0)
+
+            def on_focus_change(self, focused):
+                super().on_focus_change(focused)
+                self.changes.append(focused)
+
+        h = Focus()
+        with self.create_tui_loop(h) as loop:
+            tl = loop.terminal_input_loop()
+            loop.callbacks.colorbuf = ''
+            r, w = os.pipe()
+            try:
+                os.write(w, b'\x1b[O')
+                tl._read_ready(h, r)
+                self.ae(h.changes, [False])
+                self.assertFalse(h.has_focus)
+                self.ae(loop.callbacks.colorbuf, '#646464#320000')
+                os.write(w, b'a\x1b[Ib')
+                tl._read_ready(h, r)
+            finally:
+                os.close(r), os.close(w)
+            self.ae(h.changes, [False, True])
+            self.assertTrue(h.has_focus)
+            self.ae(loop.callbacks.colorbuf, '#646464#320000#c8c8c8#640000')
+
+    def test_write_stall(self):
+        import os
+        from time import monotonic
+
+        from kittens.tui.handler import Handler
+
+        class Writer(Handler):
+            write_stall_timeout = 0.01
+
+            def initialize(self):
+                self.stalls = []
+                self.finished = False
+
+            def on_write_stalled(self, stalled):
+                self.stalls.append(stalled)
+
+            def on_writing_finished(self):
+                self.finished = True
+
+        h = Writer()
+        with self.create_tui_loop(h) as loop:
+            tl = loop.terminal_input_loop()
+            r, w = os.pipe()
+            os.set_blocking(r, False), os.set_blocking(w, False)
+            try:
+                # fill the pipe so that the terminal side does not drain writes
+                while True:
+                    try:
+                        os.write(w, b'x' * 4096)
+                    except BlockingIOError:
+                        break
+                tl.write_buf, tl.waiting_for_writes, tl.write_stalled = [b'pending output'], True, False
+                tl.last_write_progress_at, tl.write_stall_check = monotonic(), None
+                tl._arm_write_stall_check(h)
+                tl._write_ready(h, w)
+                self.ae(h.stalls, [])
+                loop.run_pending()
+                self.ae(h.stalls, [True])
+                tl._write_ready(h, w)
+                self.ae(h.stalls, [True])
+                while True:
+                    try:
+                        if not os.read(r, 65536):
+                            break
+                    except BlockingIOError:
+                        break
+                tl._write_ready(h, w)
+                loop.run_pending()
+                self.ae(h.stalls, [True, False])
+                self.assertTrue(h.finished)
+                self.ae(tl.write_buf, [])
+                self.assertFalse(tl.waiting_for_writes)
+            finally:
+                os.close(r), os.close(w)
+
     def test_multiprocessing_spawn(self):
         from kitty.multiprocessing import test_spawn
         test_spawn()